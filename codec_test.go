@@ -0,0 +1,201 @@
+package lol_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	lol "github.com/kdy1997/go-lol"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// Tests and benchmarks in this file run against generated JSON methods, and
+// against encoding/json with -tags lol_reflectjson. e.g.
+//
+//	go test -run NONE -bench . github.com/kdy1997/go-lol
+//	go test -run NONE -bench . -tags lol_reflectjson github.com/kdy1997/go-lol
+
+// fill sets deterministic values to every field of v, with n elements per slice and map.
+func fill(v reflect.Value, n int, seq *int) {
+	*seq++
+	switch v.Kind() {
+	case reflect.Bool:
+		v.SetBool(*seq%2 == 0)
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		v.SetInt(int64(*seq) * 7919)
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(float64(*seq) / 8)
+	case reflect.String:
+		v.SetString(fmt.Sprintf("value %d", *seq))
+	case reflect.Ptr:
+		v.Set(reflect.New(v.Type().Elem()))
+		fill(v.Elem(), n, seq)
+	case reflect.Slice:
+		v.Set(reflect.MakeSlice(v.Type(), n, n))
+		for i := 0; i < n; i++ {
+			fill(v.Index(i), n, seq)
+		}
+	case reflect.Map:
+		v.Set(reflect.MakeMap(v.Type()))
+		for i := 0; i < n; i++ {
+			key, elem := reflect.New(v.Type().Key()).Elem(), reflect.New(v.Type().Elem()).Elem()
+			fill(key, n, seq)
+			fill(elem, n, seq)
+			v.SetMapIndex(key, elem)
+		}
+	case reflect.Struct:
+		if v.Type() == reflect.TypeOf(lol.SpellRange{}) {
+			// Self is not encoded by SpellRange.
+			v.Set(reflect.ValueOf(lol.SpellRange{Ranges: []int32{int32(*seq), 600}}))
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			fill(v.Field(i), n, seq)
+		}
+	default:
+		panic("fill: unknown kind " + v.Kind().String())
+	}
+}
+
+func filled(v interface{}, n int) interface{} {
+	var seq int
+	fill(reflect.ValueOf(v).Elem(), n, &seq)
+	return v
+}
+
+func TestCodec(t *testing.T) {
+	Convey("JSON methods of response classes", t, func() {
+		Convey("Round-trip every field", func() {
+			for _, v := range []interface{}{
+				filled(&lol.MatchDetail{}, 3),
+				filled(&lol.Champions{}, 3),
+				filled(&lol.Summoner{}, 3),
+			} {
+				data, err := json.Marshal(v)
+				So(err, ShouldBeNil)
+
+				decoded := reflect.New(reflect.TypeOf(v).Elem()).Interface()
+				So(json.Unmarshal(data, decoded), ShouldBeNil)
+				So(decoded, ShouldResemble, v)
+			}
+		})
+
+		Convey("Follow encoding/json", func() {
+			var s lol.Summoner
+			err := json.Unmarshal([]byte(`{
+				"id": 585897,
+				"name": "RiotSchmick 😀",
+				"profileIconId": null,
+				"unknown": {"nested": [1, "two", null, true]},
+				"summonerLevel": 30
+			}`), &s)
+			So(err, ShouldBeNil)
+			So(s, ShouldResemble, lol.Summoner{ID: 585897, Name: "RiotSchmick 😀", SummonerLevel: 30})
+
+			data, err := json.Marshal(&lol.Image{Full: "<a&b>", X: 1})
+			So(err, ShouldBeNil)
+			So(string(data), ShouldEqual, `{"full":"\u003ca\u0026b\u003e","group":"","h":0,"sprite":"","w":0,"x":1,"y":0}`)
+
+			for _, name := range []string{"\"quoted\"\n\t\\", "é 😀 \u2028", "\x00\x1f", "\xff"} {
+				data, err := json.Marshal(&lol.Image{Full: name})
+				So(err, ShouldBeNil)
+				var image lol.Image
+				So(json.Unmarshal(data, &image), ShouldBeNil)
+				So(image.Full, ShouldEqual, strings.ToValidUTF8(name, "\uFFFD"))
+			}
+
+			data, err = json.Marshal(&lol.Champions{})
+			So(err, ShouldBeNil)
+			So(string(data), ShouldEqual, `{"data":null,"format":"","keys":null,"type":"","version":""}`)
+		})
+
+		Convey("Match keys case-insensitively", func() {
+			var s lol.Summoner
+			err := json.Unmarshal([]byte(`{"ID": 1, "Name": "a", "SUMMONERLEVEL": 30, "name": "b", "NAME": "c"}`), &s)
+			So(err, ShouldBeNil)
+			So(s, ShouldResemble, lol.Summoner{ID: 1, Name: "c", SummonerLevel: 30})
+
+			var image lol.Image
+			So(json.Unmarshal([]byte(`{"x": 1, "X": 2}`), &image), ShouldBeNil)
+			So(image.X, ShouldEqual, 2)
+		})
+
+		Convey("Use SpellRange.UnmarshalJSON", func() {
+			var spells []*lol.ChampionSpell
+			err := json.Unmarshal([]byte(`[{"range": "self"}, {"range": [600, 700]}, {"range": {"Ranges": [800]}}]`), &spells)
+			So(err, ShouldBeNil)
+			So(spells, ShouldHaveLength, 3)
+			So(spells[0].Range, ShouldResemble, &lol.SpellRange{Self: true})
+			So(spells[1].Range, ShouldResemble, &lol.SpellRange{Ranges: []int32{600, 700}})
+			So(spells[2].Range, ShouldResemble, &lol.SpellRange{Ranges: []int32{800}})
+		})
+
+		Convey("Report errors", func() {
+			var s lol.Summoner
+			for _, data := range []string{`{"id": 1 "name": ""}`, `{"id": 1,}`, `{"id": 1} {}`, `{"name": "a`, `{"name": "\x"}`, `{"id": tru}`} {
+				So(json.Unmarshal([]byte(data), &s), ShouldNotBeNil)
+			}
+			So(json.Unmarshal([]byte(`{"id": "585897"}`), &s), ShouldNotBeNil)
+			So(json.Unmarshal([]byte(`{"profileIconId": 1.5}`), &s), ShouldNotBeNil)
+			So(json.Unmarshal([]byte(`{"profileIconId": 3000000000}`), &s), ShouldNotBeNil)
+			So(json.Unmarshal([]byte(`{"id": 007}`), &s), ShouldNotBeNil)
+			So(json.Unmarshal([]byte(`{"id": 1-2}`), &s), ShouldNotBeNil)
+		})
+	})
+}
+
+func benchmarkUnmarshal(b *testing.B, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		b.Fatal(err)
+	}
+	typ := reflect.TypeOf(v).Elem()
+
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// Like the client, call generated UnmarshalJSON directly if any.
+		v := reflect.New(typ).Interface()
+		if u, ok := v.(json.Unmarshaler); ok {
+			err = u.UnmarshalJSON(data)
+		} else {
+			err = json.Unmarshal(data, v)
+		}
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchmarkMarshal(b *testing.B, v interface{}) {
+	for i := 0; i < b.N; i++ {
+		// json.Marshal validates and compacts the result of MarshalJSON, so call it directly if any.
+		var err error
+		if m, ok := v.(json.Marshaler); ok {
+			_, err = m.MarshalJSON()
+		} else {
+			_, err = json.Marshal(v)
+		}
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// matchDetail is a match with a full timeline.
+func matchDetail() *lol.MatchDetail {
+	m := filled(&lol.MatchDetail{}, 10).(*lol.MatchDetail)
+	frame := m.Timeline.Frames[0]
+	for len(m.Timeline.Frames) < 40 {
+		m.Timeline.Frames = append(m.Timeline.Frames, frame)
+	}
+	return m
+}
+
+func BenchmarkUnmarshalMatchDetail(b *testing.B) { benchmarkUnmarshal(b, matchDetail()) }
+func BenchmarkMarshalMatchDetail(b *testing.B)   { benchmarkMarshal(b, matchDetail()) }
+
+func BenchmarkUnmarshalChampions(b *testing.B) { benchmarkUnmarshal(b, filled(&lol.Champions{}, 8)) }
+func BenchmarkMarshalChampions(b *testing.B)   { benchmarkMarshal(b, filled(&lol.Champions{}, 8)) }
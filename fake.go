@@ -0,0 +1,48 @@
+package lol
+
+import "sync"
+
+// Fake is an in-memory implementation of API for tests.
+//
+// Results are set per method with SetXxx (e.g. SetSummoners), and
+// calls of a method without a result return zero value and nil error.
+// Calls made by Fake never touch the network.
+type Fake struct {
+	mu      sync.Mutex
+	results map[string]fakeResult
+	calls   map[string]int
+}
+
+type fakeResult struct {
+	value interface{}
+	err   error
+}
+
+// NewFake creates a fake without any result.
+func NewFake() *Fake {
+	return &Fake{
+		results: make(map[string]fakeResult),
+		calls:   make(map[string]int),
+	}
+}
+
+// Calls returns how many times Do was called for a method.
+func (f *Fake) Calls(method string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls[method]
+}
+
+func (f *Fake) set(method string, value interface{}, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.results[method] = fakeResult{value: value, err: err}
+}
+
+func (f *Fake) result(method string) (interface{}, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls[method]++
+	r := f.results[method]
+	return r.value, r.err
+}
@@ -0,0 +1,67 @@
+package lol_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	lol "github.com/kdy1997/go-lol"
+	"github.com/kdy1997/go-lol/loltest"
+	. "github.com/smartystreets/goconvey/convey"
+	"golang.org/x/net/context"
+)
+
+// summonerName is a code under test, which only depends on lol.SummonerAPI.
+func summonerName(api lol.SummonerAPI, region lol.Region, id int64) (string, error) {
+	names, err := api.SummonerNames(context.TODO(), region, []int64{id}).Do()
+	if err != nil {
+		return "", err
+	}
+	return names[id], nil
+}
+
+func TestFake(t *testing.T) {
+	Convey("Fake", t, func() {
+		fake := lol.NewFake()
+
+		Convey("Returns result set by SetXxx", func() {
+			fake.SetSummonerNames(map[int64]string{585897: "RiotSchmick"}, nil)
+
+			name, err := summonerName(fake, lol.NA, 585897)
+			So(err, ShouldBeNil)
+			So(name, ShouldEqual, "RiotSchmick")
+			So(fake.Calls("SummonerNames"), ShouldEqual, 1)
+		})
+
+		Convey("Returns error set by SetXxx", func() {
+			want := errors.New("unavailable")
+			fake.SetSummonerNames(nil, want)
+
+			_, err := summonerName(fake, lol.NA, 585897)
+			So(err, ShouldEqual, want)
+		})
+
+		Convey("Returns zero value if result is not set", func() {
+			summoners, err := fake.Summoners(context.TODO(), lol.KR, []int64{1}).Do()
+			So(err, ShouldBeNil)
+			So(summoners, ShouldBeNil)
+			So(fake.Calls("Summoners"), ShouldEqual, 1)
+		})
+
+		Convey("Is interchangeable with Client.API", func() {
+			factory := loltest.ClientFactory(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{"585897": "RiotSchmick"}`))
+			}))
+			name, err := summonerName(lol.New(factory, "key").API(), lol.NA, 585897)
+			So(err, ShouldBeNil)
+			So(name, ShouldEqual, "RiotSchmick")
+		})
+
+		Convey("Works for operations without result", func() {
+			fake.SetUpdateTournamentCode(lol.HTTPError{Code: 404})
+
+			err := fake.UpdateTournamentCode(context.TODO(), "code").Body(&lol.TournamentCodeUpdateParameters{}).Do()
+			So(err, ShouldHaveSameTypeAs, lol.HTTPError{})
+		})
+	})
+}
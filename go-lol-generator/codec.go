@@ -0,0 +1,284 @@
+package main
+
+import (
+	"fmt"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"github.com/kdy1997/go-lol/go-lol-generator/loldoc"
+)
+
+const jsonCodecPkg = pkgPath + "/internal/jsoncodec"
+
+// codecBuildTag disables generated JSON methods, falling back to reflection.
+const codecBuildTag = "lol_reflectjson"
+
+// GenerateCodec generates UnmarshalJSON and MarshalJSON of every response class,
+// which decode and encode without reflection.
+func (g *Generator) GenerateCodec() []byte {
+	g.P(`// Generated by go-lol-generator. DO NOT EDIT.`)
+	g.P()
+	g.P(`// +build !`, codecBuildTag)
+	g.P()
+	g.P(`package `, g.pkgName)
+	g.P()
+	g.P(`import "sort"`)
+	g.P(`import `, strconv.Quote(jsonCodecPkg))
+	g.P()
+	g.P(`var _ = sort.Strings`)
+
+	g.classes = make(map[string]bool)
+	for _, res := range g.doc.Resources {
+		for _, s := range res.Definitions {
+			g.classes[typeNameOf(s)] = true
+		}
+	}
+
+	for _, res := range g.doc.Resources {
+		for _, s := range res.SortedDefinitions() {
+			if s.SharedName != "" {
+				// methods of a shared type are generated once.
+				if g.shared[s.SharedName] {
+					continue
+				}
+				g.shared[s.SharedName] = true
+			}
+			g.generateClassCodec(typeNameOf(s), s)
+		}
+	}
+	return g.Bytes()
+}
+
+// typeNameOf returns name of the struct generated for s.
+func typeNameOf(s loldoc.Schema) string {
+	if s.SharedName != "" {
+		return s.SharedName
+	}
+	return s.StructName
+}
+
+func (g *Generator) generateClassCodec(name string, s loldoc.Schema) {
+	g.P()
+	g.P(`// UnmarshalJSON implements json.Unmarshaler without reflection.`)
+	g.P(`func (v *`, name, `) UnmarshalJSON(data []byte) error {`)
+	g.P(`l := jsoncodec.NewLexer(data)`)
+	g.P(`v.decodeJSON(l)`)
+	g.P(`return l.Done()`)
+	g.P(`}`)
+	g.P()
+
+	g.P(`// MarshalJSON implements json.Marshaler without reflection.`)
+	g.P(`func (v *`, name, `) MarshalJSON() ([]byte, error) {`)
+	g.P(`w := jsoncodec.NewWriter()`)
+	g.P(`v.encodeJSON(w)`)
+	g.P(`return w.Bytes()`)
+	g.P(`}`)
+	g.P()
+
+	keys := make([]string, len(s.Fields))
+	for i, f := range s.Fields {
+		keys[i] = strconv.Quote(f.OrigName())
+	}
+	keysVar := funcName(name, false) + "JSONKeys"
+	g.P(`var `, keysVar, ` = []string{`, strings.Join(keys, ", "), `}`)
+	g.P()
+
+	g.P(`func (v *`, name, `) decodeJSON(l *jsoncodec.Lexer) {`)
+	g.P(`if l.Null() { return }`)
+	g.P(`l.Delim('{')`)
+	g.P(`for l.More() {`)
+	g.P(`key := l.Key()`)
+	g.P(`if !v.decodeField(l, string(key)) && !v.decodeField(l, jsoncodec.FoldKey(key, `, keysVar, `)) {`)
+	g.P(`l.Skip()`)
+	g.P(`}`)
+	g.P(`}`)
+	g.P(`l.Delim('}')`)
+	g.P(`}`)
+	g.P()
+
+	g.P(`// decodeField reads the value of key, or returns false if v has no such field.`)
+	g.P(`func (v *`, name, `) decodeField(l *jsoncodec.Lexer, key string) bool {`)
+	g.P(`switch key {`)
+	for _, f := range s.Fields {
+		g.P(`case `, strconv.Quote(f.OrigName()), `:`)
+		g.decodeValue("v."+f.GoName(), f.Type, 0)
+	}
+	g.P(`default:`)
+	g.P(`return false`)
+	g.P(`}`)
+	g.P(`return true`)
+	g.P(`}`)
+	g.P()
+
+	g.P(`func (v *`, name, `) encodeJSON(w *jsoncodec.Writer) {`)
+	for i, f := range s.Fields {
+		sep := ","
+		if i == 0 {
+			sep = "{"
+		}
+		g.P(`w.Raw(`, strconv.Quote(sep+strconv.Quote(f.OrigName())+":"), `)`)
+		g.encodeValue("v."+f.GoName(), f.Type, 0)
+	}
+	if len(s.Fields) == 0 {
+		g.P(`w.Raw("{}")`)
+	} else {
+		g.P(`w.Raw("}")`)
+	}
+	g.P(`}`)
+}
+
+// classOf returns name of the response class pointed by t, or "" if t is not such a pointer.
+func (g *Generator) classOf(t types.Type) string {
+	p, ok := t.(*types.Pointer)
+	if !ok {
+		return ""
+	}
+	named, ok := p.Elem().(*types.Named)
+	if !ok || !g.classes[named.Obj().Name()] {
+		return ""
+	}
+	return named.Obj().Name()
+}
+
+// basicCodecMethod returns name of Lexer and Writer methods for t, or "" if there is no such method.
+func basicCodecMethod(t types.Type) string {
+	b, ok := t.(*types.Basic)
+	if !ok {
+		return ""
+	}
+	switch b.Kind() {
+	case types.Bool:
+		return "Bool"
+	case types.Int:
+		return "Int"
+	case types.Int32:
+		return "Int32"
+	case types.Int64:
+		return "Int64"
+	case types.Float32:
+		return "Float32"
+	case types.Float64:
+		return "Float64"
+	case types.String:
+		return "String"
+	}
+	return ""
+}
+
+func isStringMap(t types.Type) bool {
+	m, ok := t.(*types.Map)
+	return ok && basicCodecMethod(m.Key()) == "String"
+}
+
+// decodeValue generates statements reading a value of type t into target.
+// depth is used to name temporary variables of nested slices and maps.
+func (g *Generator) decodeValue(target string, t types.Type, depth int) {
+	if m := basicCodecMethod(t); m != "" {
+		g.P(`if !l.Null() { `, target, ` = l.`, m, `() }`)
+		return
+	}
+	if class := g.classOf(t); class != "" {
+		g.P(`if l.Null() {`)
+		g.P(target, ` = nil`)
+		g.P(`} else {`)
+		g.P(`if `, target, ` == nil { `, target, ` = new(`, class, `) }`)
+		g.P(target, `.decodeJSON(l)`)
+		g.P(`}`)
+		return
+	}
+
+	switch t := t.(type) {
+	case *types.Slice:
+		elem := fmt.Sprintf("e%d", depth)
+		g.P(`if l.Null() {`)
+		g.P(target, ` = nil`)
+		g.P(`} else {`)
+		g.P(`l.Delim('[')`)
+		g.P(target, ` = make(`, t, `, 0)`)
+		g.P(`for l.More() {`)
+		g.P(`var `, elem, ` `, t.Elem())
+		g.decodeValue(elem, t.Elem(), depth+1)
+		g.P(target, ` = append(`, target, `, `, elem, `)`)
+		g.P(`}`)
+		g.P(`l.Delim(']')`)
+		g.P(`}`)
+		return
+
+	case *types.Map:
+		if !isStringMap(t) {
+			break
+		}
+		key, elem := fmt.Sprintf("k%d", depth), fmt.Sprintf("e%d", depth)
+		g.P(`if l.Null() {`)
+		g.P(target, ` = nil`)
+		g.P(`} else {`)
+		g.P(`l.Delim('{')`)
+		g.P(`if `, target, ` == nil { `, target, ` = make(`, t, `) }`)
+		g.P(`for l.More() {`)
+		g.P(key, ` := string(l.Key())`)
+		g.P(`var `, elem, ` `, t.Elem())
+		g.decodeValue(elem, t.Elem(), depth+1)
+		g.P(target, `[`, key, `] = `, elem)
+		g.P(`}`)
+		g.P(`l.Delim('}')`)
+		g.P(`}`)
+		return
+	}
+
+	// e.g. SpellRange, which has its own UnmarshalJSON.
+	g.P(`l.Unmarshal(&`, target, `)`)
+}
+
+// encodeValue generates statements writing expr of type t.
+func (g *Generator) encodeValue(expr string, t types.Type, depth int) {
+	if m := basicCodecMethod(t); m != "" {
+		g.P(`w.`, m, `(`, expr, `)`)
+		return
+	}
+	if class := g.classOf(t); class != "" {
+		g.P(`if `, expr, ` == nil { w.Raw("null") } else { `, expr, `.encodeJSON(w) }`)
+		return
+	}
+
+	switch t := t.(type) {
+	case *types.Slice:
+		i, elem := fmt.Sprintf("i%d", depth), fmt.Sprintf("e%d", depth)
+		g.P(`if `, expr, ` == nil {`)
+		g.P(`w.Raw("null")`)
+		g.P(`} else {`)
+		g.P(`w.Raw("[")`)
+		g.P(`for `, i, `, `, elem, ` := range `, expr, ` {`)
+		g.P(`if `, i, ` > 0 { w.Raw(",") }`)
+		g.encodeValue(elem, t.Elem(), depth+1)
+		g.P(`}`)
+		g.P(`w.Raw("]")`)
+		g.P(`}`)
+		return
+
+	case *types.Map:
+		if !isStringMap(t) {
+			break
+		}
+		// keys are sorted like encoding/json.
+		i, keys, key := fmt.Sprintf("i%d", depth), fmt.Sprintf("keys%d", depth), fmt.Sprintf("k%d", depth)
+		g.P(`if `, expr, ` == nil {`)
+		g.P(`w.Raw("null")`)
+		g.P(`} else {`)
+		g.P(keys, ` := make([]string, 0, len(`, expr, `))`)
+		g.P(`for `, key, ` := range `, expr, ` { `, keys, ` = append(`, keys, `, `, key, `) }`)
+		g.P(`sort.Strings(`, keys, `)`)
+		g.P(`w.Raw("{")`)
+		g.P(`for `, i, `, `, key, ` := range `, keys, ` {`)
+		g.P(`if `, i, ` > 0 { w.Raw(",") }`)
+		g.P(`w.String(`, key, `)`)
+		g.P(`w.Raw(":")`)
+		g.encodeValue(expr+`[`+key+`]`, t.Elem(), depth+1)
+		g.P(`}`)
+		g.P(`w.Raw("}")`)
+		g.P(`}`)
+		return
+	}
+
+	g.P(`w.Marshal(`, expr, `)`)
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/kdy1997/go-lol/go-lol-generator/loldoc"
+	"github.com/kdy1997/go-lol/go-lol-generator/patcher"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+// runDiff implements `go-lol-generator diff old.html new.html`.
+func runDiff(c context.Context, args []string) error {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "print changes as json")
+	patches := fs.String("patches", *patchesFile, "path to patch manifest")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: go-lol-generator diff [flags] old.html new.html")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 2 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	var docs [2]*loldoc.Doc
+	for i, src := range fs.Args() {
+		if err := patcher.LoadFile(*patches); err != nil {
+			return errors.Wrap(err, "failed to load patches")
+		}
+
+		doc, err := parseInputs(c, src, nil)
+		// names of classes and operations do not matter for diff.
+		if _, ok := errors.Cause(err).(patcher.ErrPatchesRequired); ok && doc != nil {
+			err = nil
+		}
+		if err != nil {
+			return err
+		}
+		docs[i] = doc
+	}
+
+	changes := loldoc.Diff(docs[0], docs[1])
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if changes == nil {
+			changes = make([]loldoc.Change, 0)
+		}
+		return enc.Encode(changes)
+	}
+
+	for _, ch := range changes {
+		fmt.Println(ch)
+	}
+	return nil
+}
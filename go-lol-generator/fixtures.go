@@ -0,0 +1,194 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"go/types"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/kdy1997/go-lol/go-lol-generator/loldoc"
+)
+
+// spellRangeFixture is a sample of SpellRange, which is declared by hand.
+// Self is not encoded, so it must be false to round-trip.
+const spellRangeFixture = `{"Self":false,"Ranges":[600]}`
+
+// GenerateFixtures generates a test file of package pkgName+"_test", which has a sample json
+// of every response class and checks that it round-trips through the generated struct.
+// Documented examples of classes are decoded as well.
+func (g *Generator) GenerateFixtures() []byte {
+	g.P(`// Generated by go-lol-generator. DO NOT EDIT.`)
+	g.P()
+	g.P(`package `, g.pkgName, `_test`)
+	g.P()
+	g.P(`import (`)
+	g.P(`"encoding/json"`)
+	g.P(`"testing"`)
+	g.P()
+	g.P(g.pkgName, ` `, strconv.Quote(pkgPath))
+	g.P(`. "github.com/smartystreets/goconvey/convey"`)
+	g.P(`)`)
+	g.P()
+
+	schemas := make(map[string]loldoc.Schema)
+	for _, res := range g.doc.Resources {
+		for _, s := range res.Definitions {
+			schemas[typeNameOf(s)] = s
+		}
+	}
+
+	g.P(`// fixtures are sample values of every response class. Values of fields come from`)
+	g.P(`// the document when it has examples of them.`)
+	g.P(`var fixtures = []struct {`)
+	g.P(`name    string`)
+	g.P(`new     func() interface{}`)
+	g.P(`json    string`)
+	g.P(`example string // documented example of the class, if any`)
+	g.P(`}{`)
+	for _, res := range g.doc.Resources {
+		for _, s := range res.SortedDefinitions() {
+			name := typeNameOf(s)
+			if s.SharedName != "" {
+				// a shared type is tested once.
+				if g.shared[s.SharedName] {
+					continue
+				}
+				g.shared[s.SharedName] = true
+			}
+			sample := sampleClass(schemas, s, map[string]bool{name: true})
+			g.P(`{`, strconv.Quote(name), `, func() interface{} { return new(`, g.pkgName, `.`, name, `) }, `,
+				goString(sample), `, `, goString(s.Example), `},`)
+		}
+	}
+	g.P(`}`)
+	g.P()
+
+	g.P(`func TestFixtures(t *testing.T) {`)
+	g.P(`Convey("Fixtures round-trip through response classes", t, func() {`)
+	g.P(`for _, f := range fixtures {`)
+	g.P(`f := f`)
+	g.P(`Convey(f.name, func() {`)
+	g.P(`v := f.new()`)
+	g.P(`So(json.Unmarshal([]byte(f.json), v), ShouldBeNil)`)
+	g.P(`data, err := json.Marshal(v)`)
+	g.P(`So(err, ShouldBeNil)`)
+	g.P()
+	g.P(`// compare values rather than bytes, since keys may be reordered.`)
+	g.P(`var want, got interface{}`)
+	g.P(`So(json.Unmarshal([]byte(f.json), &want), ShouldBeNil)`)
+	g.P(`So(json.Unmarshal(data, &got), ShouldBeNil)`)
+	g.P(`So(got, ShouldResemble, want)`)
+	g.P()
+	g.P(`if f.example != "" {`)
+	g.P(`So(json.Unmarshal([]byte(f.example), f.new()), ShouldBeNil)`)
+	g.P(`}`)
+	g.P(`})`)
+	g.P(`}`)
+	g.P(`})`)
+	g.P(`}`)
+	return g.Bytes()
+}
+
+// sampleClass returns a json object with a sample value of every field of s.
+// visiting has names of classes being sampled, whose references are null to stop cycles.
+func sampleClass(schemas map[string]loldoc.Schema, s loldoc.Schema, visiting map[string]bool) string {
+	var buf bytes.Buffer
+	buf.WriteString("{")
+	for i, f := range s.Fields {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		buf.WriteString(strconv.Quote(f.OrigName()) + ":")
+		if f.Example != "" && exampleFits(f.Type, f.Example) {
+			buf.WriteString(f.Example)
+		} else {
+			buf.WriteString(sampleValue(schemas, f.Type, f.OrigName(), visiting))
+		}
+	}
+	buf.WriteString("}")
+	return buf.String()
+}
+
+// sampleValue returns a json value of type t. Strings are name, so that values are told apart.
+func sampleValue(schemas map[string]loldoc.Schema, t types.Type, name string, visiting map[string]bool) string {
+	switch t := t.(type) {
+	case *types.Basic:
+		switch {
+		case t.Info()&types.IsBoolean != 0:
+			return "true"
+		case t.Info()&types.IsInteger != 0:
+			return "1"
+		case t.Info()&types.IsFloat != 0:
+			return "1.5"
+		case t.Info()&types.IsString != 0:
+			return strconv.Quote(name)
+		}
+
+	case *types.Pointer:
+		return sampleValue(schemas, t.Elem(), name, visiting)
+
+	case *types.Named:
+		typeName := t.Obj().Name()
+		if typeName == "SpellRange" {
+			return spellRangeFixture
+		}
+		s, ok := schemas[typeName]
+		if !ok || visiting[typeName] {
+			return "null"
+		}
+		visiting[typeName] = true
+		defer delete(visiting, typeName)
+		return sampleClass(schemas, s, visiting)
+
+	case *types.Slice:
+		return "[" + sampleValue(schemas, t.Elem(), name, visiting) + "]"
+
+	case *types.Map:
+		key := strconv.Quote(name)
+		if b, ok := t.Key().(*types.Basic); ok && b.Info()&types.IsInteger != 0 {
+			key = `"1"`
+		}
+		return "{" + key + ":" + sampleValue(schemas, t.Elem(), name, visiting) + "}"
+	}
+	return "null"
+}
+
+// exampleFits reports whether example is a json value of basic type t.
+// Examples of other types are not used, since they may omit fields, and
+// zero values are not used either, since the document uses them as placeholders.
+func exampleFits(t types.Type, example string) bool {
+	b, ok := t.(*types.Basic)
+	if !ok {
+		return false
+	}
+	var v interface{}
+	switch {
+	case b.Info()&types.IsBoolean != 0:
+		v = new(bool)
+	case b.Kind() == types.Int64:
+		v = new(int64)
+	case b.Info()&types.IsInteger != 0:
+		v = new(int32)
+	case b.Info()&types.IsFloat != 0:
+		v = new(float64)
+	case b.Info()&types.IsString != 0:
+		v = new(string)
+	default:
+		return false
+	}
+	if err := json.Unmarshal([]byte(example), v); err != nil {
+		return false
+	}
+	zero := reflect.Zero(reflect.TypeOf(v).Elem()).Interface()
+	return reflect.ValueOf(v).Elem().Interface() != zero
+}
+
+// goString returns s as a go string literal, preferring a raw string.
+func goString(s string) string {
+	if strings.Contains(s, "`") {
+		return strconv.Quote(s)
+	}
+	return "`" + s + "`"
+}
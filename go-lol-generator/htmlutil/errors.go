@@ -0,0 +1,169 @@
+package htmlutil
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/yosssi/gohtml"
+	"golang.org/x/net/html"
+)
+
+const (
+	snippetLines     = 8   // maximum number of lines of ParseError.Snippet
+	snippetLineWidth = 120 // maximum length of a line of ParseError.Snippet
+)
+
+// ParseError is an error found at a node of a document, e.g. when the structure of riot document changes.
+//
+// Sel reports CSSPath, Snippet and Err. Resource, Operation and Block are
+// filled by the parser which knows them, and are empty if unknown.
+type ParseError struct {
+	Resource  string // e.g. "summoner"
+	Operation string // e.g. "GET /api/lol/{region}/v1.4/summoner/{summonerIds}"
+	Block     string // heading of the api block, e.g. "Query Parameters"
+
+	// CSSPath locates the node, e.g. "li.resource:nth-of-type(3) > div.heading > h2".
+	CSSPath string
+	// Snippet is the html of the node, trimmed to a few lines.
+	Snippet string
+
+	Err error
+}
+
+func (e *ParseError) Error() string {
+	var buf bytes.Buffer
+	buf.WriteString("parse error")
+	var loc []string
+	if e.Resource != "" {
+		loc = append(loc, "resource "+strconv.Quote(e.Resource))
+	}
+	if e.Operation != "" {
+		loc = append(loc, "operation "+strconv.Quote(e.Operation))
+	}
+	if e.Block != "" {
+		loc = append(loc, "block "+strconv.Quote(e.Block))
+	}
+	if len(loc) != 0 {
+		buf.WriteString(" in " + strings.Join(loc, ", "))
+	}
+	if e.CSSPath != "" {
+		buf.WriteString(" at " + e.CSSPath)
+	}
+	buf.WriteString(": ")
+	buf.WriteString(e.Err.Error())
+	if e.Snippet != "" {
+		buf.WriteString("\n")
+		for _, line := range strings.Split(e.Snippet, "\n") {
+			buf.WriteString("\t" + line + "\n")
+		}
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// Unwrap returns the underlying error.
+func (e *ParseError) Unwrap() error { return e.Err }
+
+// Errorf returns a ParseError located at sel.
+func (sel Sel) Errorf(format string, args ...interface{}) *ParseError {
+	return newParseError(sel.Selection, fmt.Errorf(format, args...))
+}
+
+// WithDump returns err as a ParseError located at sel, with a snippet of sel.
+// If err is already a ParseError, it's returned as it is.
+func (sel Sel) WithDump(err error) error {
+	if pe, ok := err.(*ParseError); ok {
+		return pe
+	}
+	return newParseError(sel.Selection, err)
+}
+
+func newParseError(s *goquery.Selection, err error) *ParseError {
+	return &ParseError{
+		CSSPath: CSSPath(s),
+		Snippet: Snippet(s),
+		Err:     err,
+	}
+}
+
+// CSSPath returns a selector locating the first node of s, from the closest ancestor with id
+// or the root of the document. Siblings of the same tag are told apart by :nth-of-type.
+func CSSPath(s *goquery.Selection) string {
+	if len(s.Nodes) == 0 {
+		return ""
+	}
+
+	var path []string
+	for n := s.Nodes[0]; n != nil && n.Type == html.ElementNode; n = n.Parent {
+		sel := nodeSelector(n)
+		path = append(path, sel)
+		if strings.Contains(sel, "#") {
+			break // the path is unique from a node with id.
+		}
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return strings.Join(path, " > ")
+}
+
+func nodeSelector(n *html.Node) string {
+	var id, classes string
+	for _, attr := range n.Attr {
+		switch attr.Key {
+		case "id":
+			id = "#" + attr.Val
+		case "class":
+			for _, class := range strings.Fields(attr.Val) {
+				classes += "." + class
+			}
+		}
+	}
+	if id != "" { // ids are unique.
+		return n.Data + id + classes
+	}
+
+	nth, count := 0, 0
+	if n.Parent != nil {
+		for c := n.Parent.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == html.ElementNode && c.Data == n.Data {
+				count++
+				if c == n {
+					nth = count
+				}
+			}
+		}
+	}
+	if count > 1 {
+		return fmt.Sprintf("%s%s:nth-of-type(%d)", n.Data, classes, nth)
+	}
+	return n.Data + classes
+}
+
+// Snippet returns html of s, trimmed to a few short lines.
+func Snippet(s *goquery.Selection) string {
+	if len(s.Nodes) == 0 {
+		return "<!-- EMPTY NODE -->"
+	}
+	str, err := goquery.OuterHtml(s.First())
+	if err != nil {
+		return ""
+	}
+
+	lines := strings.Split(strings.TrimSpace(gohtml.Format(str)), "\n")
+	trimmed := len(lines) > snippetLines
+	if trimmed {
+		lines = lines[:snippetLines]
+	}
+	for i, line := range lines {
+		if len(line) > snippetLineWidth {
+			lines[i] = line[:snippetLineWidth] + "..."
+		}
+	}
+	if trimmed {
+		lines = append(lines, "...")
+	}
+	return strings.Join(lines, "\n")
+}
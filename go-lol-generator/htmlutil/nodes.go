@@ -17,7 +17,7 @@ type Sels []Sel
 
 func Wrap(s *goquery.Selection) Sel {
 	if s.Length() != 1 {
-		panic(errors.Errorf("htmlutil: invalid selector %s", Dump(s)))
+		panic(newParseError(s, errors.Errorf("expected a single node, but got %d", s.Length())))
 	}
 	return Sel{s}
 }
@@ -33,8 +33,7 @@ func WrapAll(s *goquery.Selection) Sels {
 // NOTE: text replaces 's' with comment
 func (sel Sel) Text() string {
 	if cnt := len(sel.Selection.Children().Nodes); cnt != 0 {
-		err := errors.Errorf("cannot read text: node must not have a child, but has %d", cnt)
-		panic(sel.WithDump(err))
+		panic(sel.Errorf("cannot read text: node must not have a child, but has %d", cnt))
 	}
 
 	s := sel.Selection.Contents()
@@ -54,8 +53,7 @@ func (sel Sel) Text() string {
 
 func (sel Sel) EatText() string {
 	if cnt := len(sel.Selection.Children().Nodes); cnt != 0 {
-		err := errors.Errorf("cannot read text: node must not have a child, but has %d", cnt)
-		panic(sel.WithDump(err))
+		panic(sel.Errorf("cannot read text: node must not have a child, but has %d", cnt))
 	}
 
 	s := sel.Selection.Contents()
@@ -77,15 +75,14 @@ func (sel Sel) EatText() string {
 func (sel Sel) EatExact(text string) {
 	got := sel.Text()
 	if got != text {
-		panic(errors.Errorf("EatText: want %q, but got %q", text, got))
+		panic(sel.Errorf("EatExact: want %q, but got %q", text, got))
 	}
 	sel.Remove()
 }
 
 func (sel Sel) Ensure(selector string) Sel {
 	if !sel.Selection.Is(selector) {
-		err := errors.Errorf("expected selector: %q\n", selector)
-		panic(sel.WithDump(err))
+		panic(sel.Errorf("expected selector %q", selector))
 	}
 	return sel
 }
@@ -106,17 +103,15 @@ func (sel Sel) Dump() string {
 	return Dump(sel.Selection)
 }
 
-// WithDump wraps err with error with dump data.
-func (sel Sel) WithDump(err error) error {
-	return errors.Wrap(err, sel.Dump())
-}
-
 func (ss Sels) MustBeSingle() Sel {
 	switch len(ss) {
+	case 0:
+		panic(&ParseError{Err: errors.New("must have single node, but has 0")})
 	case 1:
 		return ss[0]
 	default:
-		panic(errors.Errorf("must have single node, but has %d", len(ss)))
+		// located at the parent, which is usually the node being parsed.
+		panic(newParseError(ss[0].Selection.Parent(), errors.Errorf("must have single node, but has %d", len(ss))))
 	}
 }
 
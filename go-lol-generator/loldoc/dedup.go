@@ -0,0 +1,163 @@
+package loldoc
+
+import (
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+
+	"github.com/kdy1997/go-lol/go-lol-generator/patcher"
+)
+
+// Dedup merges structurally identical classes of different resources into a shared type.
+//
+// Classes are identical if they have the same fields with the same types, after
+// merging classes referenced by the fields. Descriptions are not compared.
+// Merged classes get SharedName, and every type referencing them is rewritten to
+// the shared type. Classes without fields and classes patched as distinct are never merged.
+//
+// The shared type is named by sharedName of the class patches if any. Classes of versions of one resource
+// share the struct name of the newest version (see SuffixVersions). Otherwise, it's named after
+// the original name of the classes if they agree and the name is not used by another class,
+// or the struct name of the first one.
+// It returns the names of shared types.
+func Dedup(doc *Doc) []string {
+	var all []*Schema
+	defs := make(map[string]*Schema)     // by struct name
+	versions := make(map[*Schema]string) // version of the resource of each class
+	for i := range doc.Resources {
+		for _, s := range doc.Resources[i].SortedDefinitions() {
+			s := s
+			all = append(all, &s)
+			defs[s.StructName] = &s
+			versions[&s] = doc.Resources[i].Version
+		}
+	}
+
+	// canon maps a struct name to the struct name of the first identical class.
+	canon := make(map[string]string)
+	for name := range defs {
+		canon[name] = name
+	}
+	for changed := true; changed; {
+		changed = false
+		first := make(map[string]string) // by signature
+		for _, s := range all {
+			// empty classes have nothing in common but their emptiness.
+			if len(s.Fields) == 0 || patcher.ForClass(s.ResID(), s.OrigName).Distinct {
+				continue
+			}
+			sig := signature(s, canon)
+			f, ok := first[sig]
+			if !ok {
+				first[sig] = s.StructName
+				continue
+			}
+			if canon[s.StructName] != canon[f] {
+				canon[s.StructName] = canon[f]
+				changed = true
+			}
+		}
+	}
+
+	groups := make(map[string][]*Schema)
+	for _, s := range all {
+		c := canon[s.StructName]
+		groups[c] = append(groups[c], s)
+	}
+
+	renames := make(map[string]string)
+	var shared []string
+	taken := make(map[string]bool)
+	for _, s := range all {
+		g := groups[s.StructName]
+		if len(g) < 2 {
+			continue
+		}
+		name := sharedName(g, defs, versions)
+		if taken[name] {
+			name = g[0].StructName
+		}
+		taken[name] = true
+		for _, member := range g {
+			renames[member.StructName] = name
+		}
+		shared = append(shared, name)
+	}
+	if len(renames) == 0 {
+		return nil
+	}
+
+	for i := range doc.Resources {
+		res := &doc.Resources[i]
+		for key, s := range res.Definitions {
+			s.SharedName = renames[s.StructName]
+			for j, f := range s.Fields {
+				s.Fields[j].Type = renameTypes(f.Type, renames)
+			}
+			res.Definitions[key] = s
+		}
+		for _, op := range res.Operations {
+			op.OrigReturnType = renameTypes(op.OrigReturnType, renames)
+			if op.Body != nil {
+				op.Body.Type = renameTypes(op.Body.Type, renames)
+			}
+		}
+	}
+	sort.Strings(shared)
+	return shared
+}
+
+// signature describes fields of s, where referenced classes are replaced by canon.
+func signature(s *Schema, canon map[string]string) string {
+	var fields []string
+	for _, f := range s.Fields {
+		fields = append(fields, f.OrigName()+" "+f.GoName()+" "+types.TypeString(renameTypes(f.Type, canon), nil))
+	}
+	sort.Strings(fields)
+	return strings.Join(fields, ";")
+}
+
+func sharedName(group []*Schema, defs map[string]*Schema, versions map[*Schema]string) string {
+	for _, s := range group {
+		if name := patcher.ForClass(s.ResID(), s.OrigName).SharedName; name != "" {
+			return name
+		}
+	}
+	if name, ok := newestOf(group, versions); ok {
+		return name
+	}
+
+	name := group[0].OrigName
+	for _, s := range group {
+		if s.OrigName != name {
+			return group[0].StructName
+		}
+	}
+	if other, ok := defs[name]; ok {
+		for _, s := range group {
+			if s == other {
+				return name
+			}
+		}
+		return group[0].StructName
+	}
+	return name
+}
+
+// renameTypes returns t, where named types are renamed by names.
+func renameTypes(t types.Type, names map[string]string) types.Type {
+	switch t := t.(type) {
+	case *types.Pointer:
+		return types.NewPointer(renameTypes(t.Elem(), names))
+	case *types.Slice:
+		return types.NewSlice(renameTypes(t.Elem(), names))
+	case *types.Map:
+		return types.NewMap(renameTypes(t.Key(), names), renameTypes(t.Elem(), names))
+	case *types.Named:
+		if to, ok := names[t.Obj().Name()]; ok && to != t.Obj().Name() {
+			return types.NewNamed(types.NewTypeName(token.NoPos, t.Obj().Pkg(), to, nil), nil, nil)
+		}
+	}
+	return t
+}
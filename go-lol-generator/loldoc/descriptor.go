@@ -3,6 +3,8 @@ package loldoc
 import (
 	"fmt"
 	"go/types"
+	"sort"
+	"strconv"
 
 	"github.com/kdy1997/go-lol/go-lol-generator/patcher"
 )
@@ -17,6 +19,40 @@ type Resource struct {
 	Regions     []string
 	Definitions map[string]Schema
 	Operations  []*Operation
+
+	// Suffix is appended to names of an older version generated side by side with newer ones, from SuffixVersions.
+	Suffix string
+}
+
+// SortedDefinitions returns definitions of the resource sorted by original name.
+func (res Resource) SortedDefinitions() []Schema {
+	names := make([]string, 0, len(res.Definitions))
+	for name := range res.Definitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	defs := make([]Schema, 0, len(names))
+	for _, name := range names {
+		defs = append(defs, res.Definitions[name])
+	}
+	return defs
+}
+
+// AddOperation appends op to operations of res.
+// Front-ends other than riot.go use this to bind op to res.
+func (res *Resource) AddOperation(op *Operation) {
+	op.res = res
+	res.Operations = append(res.Operations, op)
+}
+
+// AddDefinition adds s to definitions of res, keyed by its original name.
+func (res *Resource) AddDefinition(s Schema) {
+	if res.Definitions == nil {
+		res.Definitions = make(map[string]Schema)
+	}
+	s.res = res
+	res.Definitions[s.OrigName] = s
 }
 
 type child struct {
@@ -27,6 +63,7 @@ type Operation struct {
 	child
 	PathParams  Parameters
 	QueryParams Parameters
+	Body        *Parameter // from "Body" block, nil if operation does not take a request body
 	Description string
 
 	OrigReturnType  types.Type
@@ -39,6 +76,9 @@ type Operation struct {
 	ResponseErrors []*ResponseError
 	ImplNotes      string // text from "Implementation Notes" block
 	RateLimitNotes string // text from "Rate Limit Notes"
+
+	// RequiredTogether is groups of parameters which must be set together, from ImplNotes.
+	RequiredTogether [][]string
 }
 
 type Schema struct {
@@ -48,6 +88,10 @@ type Schema struct {
 	Fields      []Field
 	OrigName    string
 	StructName  string // from override
+	// SharedName is the name of a type shared by identical classes, from Dedup. Empty if the class is not merged.
+	SharedName string
+	// Example is a documented example of the class in json (e.g. the snippet of a request body). Empty if there is none.
+	Example string
 }
 
 type Field struct {
@@ -55,6 +99,9 @@ type Field struct {
 	Type        types.Type
 	goName      string
 	Description string
+	// Example is a sample value of the field encoded in json, from a documented example of the class or
+	// the first legal value in Description. Empty if unknown.
+	Example string
 }
 
 type Parameters []Parameter
@@ -64,6 +111,13 @@ type Parameter struct {
 	Description string
 	Required    bool
 	Type        types.Type
+
+	// Enum is allowed values, from <select> of the document or patches. Empty if any value is allowed.
+	Enum []string
+	// Multiple is true if value is a comma-separated list of Enum.
+	Multiple bool
+	// MaxItems is the maximum length of a list parameter, from "Maximum allowed at once is N.". 0 if unknown.
+	MaxItems int
 }
 
 func (ps Parameters) Has(name string) bool {
@@ -81,12 +135,16 @@ type ResponseError struct {
 }
 
 func NewField(origName string, typ types.Type, description string) Field {
-	return Field{
+	f := Field{
 		origName:    origName,
 		goName:      patcher.FieldName(origName),
 		Type:        typ,
 		Description: description,
 	}
+	if vals := LegalValues(description); len(vals) != 0 && typ == types.Typ[types.String] {
+		f.Example = strconv.Quote(vals[0])
+	}
+	return f
 }
 
 func (f Field) OrigName() string { return f.origName }
@@ -102,7 +160,7 @@ func NewResponseError(code int, reason string) *ResponseError {
 // APIBase returns empty string if it's not a special operation.
 func (res Resource) APIBase() string {
 	switch res.ID {
-	case "lol-static-data":
+	case "lol-static-data", "tournament-provider":
 		return "https://global.api.pvp.net"
 	case "lol-status":
 		return "https://status.leagueoflegends.com"
@@ -111,6 +169,58 @@ func (res Resource) APIBase() string {
 	}
 }
 
+// Routing is how a request is routed to a host, when hosts of riotgames.com are used.
+type Routing int
+
+const (
+	// FixedRouting sends requests to APIBase.
+	FixedRouting Routing = iota
+	// PlatformRouting sends requests to the host of a platform, e.g. na1.api.riotgames.com.
+	PlatformRouting
+	// ClusterRouting sends requests to the host of a continental cluster, e.g. americas.api.riotgames.com.
+	ClusterRouting
+)
+
+// String returns name of the constant in package lol.
+func (r Routing) String() string {
+	switch r {
+	case PlatformRouting:
+		return "PlatformRouting"
+	case ClusterRouting:
+		return "ClusterRouting"
+	default:
+		return "FixedRouting"
+	}
+}
+
+// Routing returns how requests of the resource are routed, from "routing" of the resource patch.
+// Resources are routed to platforms by default.
+func (res Resource) Routing() Routing {
+	switch patcher.ResourceRouting(res.ID) {
+	case "fixed":
+		return FixedRouting
+	case "cluster":
+		return ClusterRouting
+	default:
+		return PlatformRouting
+	}
+}
+
+func (op Operation) Routing() Routing { return op.res.Routing() }
+
+// ReturnType returns type of the value returned by the operation, with overrided map key.
+// It returns nil if the operation does not have a response body.
+func (op *Operation) ReturnType() types.Type {
+	if op.OverridedMapKey == types.Invalid {
+		return op.OrigReturnType
+	}
+	m, ok := op.OrigReturnType.(*types.Map)
+	if !ok {
+		panic(fmt.Sprintf("loldoc: map key of %s is overrided, but it returns %v", op.MethodName, op.OrigReturnType))
+	}
+	return types.NewMap(types.Typ[op.OverridedMapKey], m.Elem())
+}
+
 // APIBase returns empty string if it's not a special operation.
 func (op Operation) APIBase() string { return op.res.APIBase() }
 
@@ -0,0 +1,291 @@
+package loldoc
+
+import (
+	"fmt"
+	"go/types"
+	"sort"
+	"strings"
+)
+
+// ChangeKind describes how an element of the document has changed.
+type ChangeKind string
+
+const (
+	Added   ChangeKind = "added"
+	Removed ChangeKind = "removed"
+	Changed ChangeKind = "changed"
+)
+
+// Change is a difference between two documents.
+type Change struct {
+	Kind     ChangeKind `json:"kind"`
+	Resource string     `json:"resource"`
+	// What is the changed element, like `operation GET /api/lol/{region}/{version}/summoner/{summonerIds}`.
+	// Paths of operations have resource version replaced with {version}.
+	What string `json:"what"`
+	Old  string `json:"old,omitempty"`
+	New  string `json:"new,omitempty"`
+}
+
+func (ch Change) String() string {
+	switch ch.Kind {
+	case Added:
+		return fmt.Sprintf("+ %s: %s", ch.Resource, ch.What)
+	case Removed:
+		return fmt.Sprintf("- %s: %s", ch.Resource, ch.What)
+	default:
+		return fmt.Sprintf("~ %s: %s: %s -> %s", ch.Resource, ch.What, ch.Old, ch.New)
+	}
+}
+
+// Diff reports differences between two documents,
+// ordered by resource id and then by element.
+func Diff(old, new *Doc) []Change {
+	d := &differ{}
+
+	olds, news := make(map[string]Resource), make(map[string]Resource)
+	var oldIDs, newIDs []string
+	for _, res := range old.Resources {
+		olds[res.ID] = res
+		oldIDs = append(oldIDs, res.ID)
+	}
+	for _, res := range new.Resources {
+		news[res.ID] = res
+		newIDs = append(newIDs, res.ID)
+	}
+
+	for _, id := range sortedUnion(oldIDs, newIDs) {
+		o, inOld := olds[id]
+		n, inNew := news[id]
+		switch {
+		case !inOld:
+			d.add(Added, id, "resource "+n.Version, "", "")
+		case !inNew:
+			d.add(Removed, id, "resource "+o.Version, "", "")
+		default:
+			d.diffResource(o, n)
+		}
+	}
+
+	return d.changes
+}
+
+type differ struct {
+	changes []Change
+}
+
+func (d *differ) add(kind ChangeKind, resID, what, old, new string) {
+	d.changes = append(d.changes, Change{
+		Kind:     kind,
+		Resource: resID,
+		What:     what,
+		Old:      old,
+		New:      new,
+	})
+}
+
+func (d *differ) diffResource(o, n Resource) {
+	id := o.ID
+	if o.Version != n.Version {
+		d.add(Changed, id, "version", o.Version, n.Version)
+	}
+	d.diffSet(id, "region", o.Regions, n.Regions)
+
+	// operations
+	oldOps, newOps := make(map[string]*Operation), make(map[string]*Operation)
+	var oldKeys, newKeys []string
+	for _, op := range o.Operations {
+		oldOps[op.key()] = op
+		oldKeys = append(oldKeys, op.key())
+	}
+	for _, op := range n.Operations {
+		newOps[op.key()] = op
+		newKeys = append(newKeys, op.key())
+	}
+	for _, key := range sortedUnion(oldKeys, newKeys) {
+		oop, inOld := oldOps[key]
+		nop, inNew := newOps[key]
+		switch {
+		case !inOld:
+			d.add(Added, id, "operation "+key, "", "")
+		case !inNew:
+			d.add(Removed, id, "operation "+key, "", "")
+		default:
+			d.diffOperation(id, "operation "+key, oop, nop)
+		}
+	}
+
+	// classes
+	oldKeys, newKeys = nil, nil
+	for name := range o.Definitions {
+		oldKeys = append(oldKeys, name)
+	}
+	for name := range n.Definitions {
+		newKeys = append(newKeys, name)
+	}
+	for _, name := range sortedUnion(oldKeys, newKeys) {
+		ocls, inOld := o.Definitions[name]
+		ncls, inNew := n.Definitions[name]
+		switch {
+		case !inOld:
+			d.add(Added, id, "class "+name, "", "")
+		case !inNew:
+			d.add(Removed, id, "class "+name, "", "")
+		default:
+			d.diffSchema(id, "class "+name, ocls, ncls)
+		}
+	}
+}
+
+func (d *differ) diffOperation(resID, what string, o, n *Operation) {
+	d.diffType(resID, what+" return value", o.OrigReturnType, n.OrigReturnType)
+	d.diffParams(resID, what+" path parameter", o.PathParams, n.PathParams)
+	d.diffParams(resID, what+" query parameter", o.QueryParams, n.QueryParams)
+
+	switch {
+	case o.Body == nil && n.Body != nil:
+		d.add(Added, resID, what+" body "+typeString(n.Body.Type), "", "")
+	case o.Body != nil && n.Body == nil:
+		d.add(Removed, resID, what+" body "+typeString(o.Body.Type), "", "")
+	case o.Body != nil && n.Body != nil:
+		d.diffType(resID, what+" body", o.Body.Type, n.Body.Type)
+	}
+
+	oldCodes, newCodes := make([]string, 0), make([]string, 0)
+	for _, e := range o.ResponseErrors {
+		oldCodes = append(oldCodes, fmt.Sprint(e.Code))
+	}
+	for _, e := range n.ResponseErrors {
+		newCodes = append(newCodes, fmt.Sprint(e.Code))
+	}
+	d.diffSet(resID, what+" response error", oldCodes, newCodes)
+
+	var oldGroups, newGroups []string
+	for _, g := range o.RequiredTogether {
+		oldGroups = append(oldGroups, strings.Join(g, "+"))
+	}
+	for _, g := range n.RequiredTogether {
+		newGroups = append(newGroups, strings.Join(g, "+"))
+	}
+	d.diffSet(resID, what+" parameters required together", oldGroups, newGroups)
+}
+
+func (d *differ) diffParams(resID, what string, o, n Parameters) {
+	olds, news := make(map[string]Parameter), make(map[string]Parameter)
+	var oldNames, newNames []string
+	for _, p := range o {
+		olds[p.Name] = p
+		oldNames = append(oldNames, p.Name)
+	}
+	for _, p := range n {
+		news[p.Name] = p
+		newNames = append(newNames, p.Name)
+	}
+
+	for _, name := range sortedUnion(oldNames, newNames) {
+		op, inOld := olds[name]
+		np, inNew := news[name]
+		switch {
+		case !inOld:
+			d.add(Added, resID, what+" "+name, "", "")
+		case !inNew:
+			d.add(Removed, resID, what+" "+name, "", "")
+		default:
+			d.diffType(resID, what+" "+name, op.Type, np.Type)
+			if op.Required != np.Required {
+				d.add(Changed, resID, what+" "+name+" required", fmt.Sprint(op.Required), fmt.Sprint(np.Required))
+			}
+			d.diffSet(resID, what+" "+name+" value", op.Enum, np.Enum)
+			if op.Multiple != np.Multiple {
+				d.add(Changed, resID, what+" "+name+" multiple", fmt.Sprint(op.Multiple), fmt.Sprint(np.Multiple))
+			}
+			if op.MaxItems != np.MaxItems {
+				d.add(Changed, resID, what+" "+name+" max items", fmt.Sprint(op.MaxItems), fmt.Sprint(np.MaxItems))
+			}
+		}
+	}
+}
+
+func (d *differ) diffSchema(resID, what string, o, n Schema) {
+	olds, news := make(map[string]Field), make(map[string]Field)
+	var oldNames, newNames []string
+	for _, f := range o.Fields {
+		olds[f.OrigName()] = f
+		oldNames = append(oldNames, f.OrigName())
+	}
+	for _, f := range n.Fields {
+		news[f.OrigName()] = f
+		newNames = append(newNames, f.OrigName())
+	}
+
+	for _, name := range sortedUnion(oldNames, newNames) {
+		of, inOld := olds[name]
+		nf, inNew := news[name]
+		switch {
+		case !inOld:
+			d.add(Added, resID, what+" field "+name+" "+typeString(nf.Type), "", "")
+		case !inNew:
+			d.add(Removed, resID, what+" field "+name+" "+typeString(of.Type), "", "")
+		default:
+			d.diffType(resID, what+" field "+name, of.Type, nf.Type)
+		}
+	}
+}
+
+func (d *differ) diffType(resID, what string, o, n types.Type) {
+	if ot, nt := typeString(o), typeString(n); ot != nt {
+		d.add(Changed, resID, what+" type", ot, nt)
+	}
+}
+
+func (d *differ) diffSet(resID, what string, o, n []string) {
+	olds, news := make(map[string]bool), make(map[string]bool)
+	for _, s := range o {
+		olds[s] = true
+	}
+	for _, s := range n {
+		news[s] = true
+	}
+
+	for _, s := range sortedUnion(o, n) {
+		switch {
+		case !olds[s]:
+			d.add(Added, resID, what+" "+s, "", "")
+		case !news[s]:
+			d.add(Removed, resID, what+" "+s, "", "")
+		}
+	}
+}
+
+// key identifies an operation across resource versions.
+func (op *Operation) key() string {
+	path := op.RequestPath
+	if v := op.res.Version; v != "" {
+		path = strings.Replace(path, "/"+v+"/", "/{version}/", 1)
+		if strings.HasSuffix(path, "/"+v) {
+			path = path[:len(path)-len(v)] + "{version}"
+		}
+	}
+	return op.HTTPMethod + " " + path
+}
+
+func typeString(t types.Type) string {
+	if t == nil {
+		return "(none)"
+	}
+	return types.TypeString(t, nil)
+}
+
+// sortedUnion returns sorted, deduplicated elements of a and b.
+func sortedUnion(a, b []string) []string {
+	seen := make(map[string]bool)
+	var ret []string
+	for _, s := range append(append([]string(nil), a...), b...) {
+		if !seen[s] {
+			seen[s] = true
+			ret = append(ret, s)
+		}
+	}
+	sort.Strings(ret)
+	return ret
+}
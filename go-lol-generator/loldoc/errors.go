@@ -0,0 +1,135 @@
+package loldoc
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kdy1997/go-lol/go-lol-generator/htmlutil"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+// ParseErrors is every error found while parsing a document.
+// The parser skips an operation or an api block on errors, and keeps parsing the rest.
+type ParseErrors []*htmlutil.ParseError
+
+func (errs ParseErrors) Error() string {
+	msgs := make([]string, len(errs))
+	for i, e := range errs {
+		msgs[i] = e.Error()
+	}
+	return fmt.Sprintf("loldoc: %d parse error(s):\n%s", len(errs), strings.Join(msgs, "\n"))
+}
+
+type errorsCtxKeyType struct{}
+
+var errorsCtxKey errorsCtxKeyType
+
+type locationCtxKeyType struct{}
+
+var locationCtxKey locationCtxKeyType
+
+// location is what is being parsed, used to locate parse errors.
+type location struct {
+	operation, block string
+}
+
+func withErrors(c context.Context) context.Context {
+	return context.WithValue(c, errorsCtxKey, &ParseErrors{})
+}
+
+func withOperation(c context.Context, op string) context.Context {
+	return context.WithValue(c, locationCtxKey, location{operation: op})
+}
+
+func withBlock(c context.Context, block string) context.Context {
+	loc, _ := c.Value(locationCtxKey).(location)
+	loc.block = block
+	return context.WithValue(c, locationCtxKey, loc)
+}
+
+// addError records err found while parsing s, so that the parser can go on.
+func addError(c context.Context, s htmlutil.Sel, err error) {
+	errs := c.Value(errorsCtxKey).(*ParseErrors)
+	*errs = append(*errs, locate(c, s, err))
+}
+
+// parseErrors returns errors recorded by addError.
+func parseErrors(c context.Context) ParseErrors {
+	return *c.Value(errorsCtxKey).(*ParseErrors)
+}
+
+// locate returns err as a ParseError, filling locations which are not set yet from c.
+// Errors which are not ParseError are located at s. Messages wrapping a ParseError
+// (e.g. "failed to parse body") are kept in its Err.
+func locate(c context.Context, s htmlutil.Sel, err error) *htmlutil.ParseError {
+	var pe *htmlutil.ParseError
+	if cause, ok := errors.Cause(err).(*htmlutil.ParseError); ok {
+		located := *cause
+		if prefix := wrapMessages(err, cause); prefix != "" {
+			located.Err = errors.Wrap(cause.Err, prefix)
+		}
+		pe = &located
+	} else {
+		pe = s.WithDump(err).(*htmlutil.ParseError)
+	}
+
+	if pe.Resource == "" {
+		pe.Resource, _ = c.Value(resIDCtxKey).(string)
+	}
+	loc, _ := c.Value(locationCtxKey).(location)
+	if pe.Operation == "" {
+		pe.Operation = loc.operation
+	}
+	if pe.Block == "" {
+		pe.Block = loc.block
+	}
+	return pe
+}
+
+// recoverParseError sets err to a ParseError panicked by htmlutil. Other panics are not recovered.
+// It must be deferred directly.
+func recoverParseError(err *error) {
+	switch r := recover().(type) {
+	case nil:
+	case *htmlutil.ParseError:
+		*err = r
+	default:
+		panic(r)
+	}
+}
+
+// wrapMessages returns messages added by wrappers of cause in err (e.g. errors.Wrap), outermost first,
+// joined by ": ". The chain is walked with Cause, or Unwrap, one wrapper at a time.
+func wrapMessages(err error, cause error) string {
+	var msgs []string
+	for err != cause {
+		inner := unwrapOnce(err)
+		if inner == nil {
+			break
+		}
+		// errors.Wrap adds "msg: " before the message of the wrapped error, and errors.WithStack adds nothing.
+		msg, innerMsg := err.Error(), inner.Error()
+		if strings.HasSuffix(msg, ": "+innerMsg) {
+			msg = strings.TrimSuffix(msg, ": "+innerMsg)
+		} else if msg == innerMsg {
+			msg = ""
+		}
+		if msg = strings.TrimSpace(msg); msg != "" {
+			msgs = append(msgs, msg)
+		}
+		err = inner
+	}
+	return strings.Join(msgs, ": ")
+}
+
+// unwrapOnce returns the error wrapped by err, or nil if err does not wrap an error.
+func unwrapOnce(err error) error {
+	switch e := err.(type) {
+	case interface{ Cause() error }:
+		return e.Cause()
+	case interface{ Unwrap() error }:
+		return e.Unwrap()
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package loldoc
+
+import (
+	"bytes"
+	"encoding/json"
+	"go/types"
+	"regexp"
+	"strings"
+
+	"github.com/kdy1997/go-lol/go-lol-generator/htmlutil"
+	"github.com/pkg/errors"
+)
+
+// legalValues matches "(Legal values: A, B, C)" in field descriptions. The closing paren is often omitted.
+var legalValues = regexp.MustCompile(`Legal values: ([^)\n]*)`)
+
+var legalValue = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// LegalValues returns values listed in description of a field, or nil if there is none.
+func LegalValues(description string) []string {
+	m := legalValues.FindStringSubmatch(description)
+	if m == nil {
+		return nil
+	}
+	var vals []string
+	for _, v := range strings.Split(m[1], ",") {
+		v = strings.TrimSpace(v)
+		if !legalValue.MatchString(v) {
+			return nil // e.g. "TOP(1)" of a number
+		}
+		vals = append(vals, v)
+	}
+	return vals
+}
+
+// consumeExample reads example json in a <pre><code class="json"> of s, and removes it.
+// It returns empty string if s does not have an example.
+func consumeExample(s htmlutil.Sel) (string, error) {
+	code := s.Selection.Find("pre code.json")
+	if code.Length() == 0 {
+		return "", nil
+	}
+	text := code.First().Text() // lines are separated by <br>, which has no text.
+	code.Remove()
+
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, []byte(text)); err != nil {
+		return "", s.WithDump(errors.Wrap(err, "invalid example json"))
+	}
+	return buf.String(), nil
+}
+
+// applyExample sets example to the class named origName, and its values to fields of the class.
+// Values of fields referring other classes of res are applied to the classes as well.
+func applyExample(res *Resource, origName, example string) {
+	cls, ok := res.Definitions[origName]
+	if !ok || cls.Example != "" {
+		return
+	}
+	cls.Example = example
+
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(example), &values); err != nil {
+		return // not an object
+	}
+	for i, f := range cls.Fields {
+		v, ok := values[f.OrigName()]
+		if !ok {
+			continue
+		}
+		cls.Fields[i].Example = string(v)
+		if ref := res.definitionOf(f.Type); ref != nil {
+			applyExample(res, ref.OrigName, string(v))
+		}
+	}
+	res.Definitions[origName] = cls
+}
+
+// definitionOf returns the class of res pointed by t, or nil if there is no such class.
+func (res *Resource) definitionOf(t types.Type) *Schema {
+	p, ok := t.(*types.Pointer)
+	if !ok {
+		return nil
+	}
+	named, ok := p.Elem().(*types.Named)
+	if !ok {
+		return nil
+	}
+	for _, s := range res.Definitions {
+		if s.StructName == named.Obj().Name() {
+			return &s
+		}
+	}
+	return nil
+}
@@ -3,12 +3,15 @@ package loldoc
 import (
 	"bytes"
 	"fmt"
+	"go/token"
+	"go/types"
 	"io/ioutil"
 	"strings"
 	"testing"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/kdy1997/go-lol/go-lol-generator/htmlutil"
+	"github.com/kdy1997/go-lol/go-lol-generator/patcher"
 	"github.com/luci/luci-go/common/logging/memlogger"
 	"github.com/pkg/errors"
 	. "github.com/smartystreets/goconvey/convey"
@@ -18,6 +21,10 @@ import (
 func TestParse(t *testing.T) { // dirty, but simple
 	c := newTestingContext()
 
+	if err := patcher.LoadFile("../patcher/patches.json"); err != nil {
+		t.Fatal(err)
+	}
+
 	data, err := ioutil.ReadFile("./methods.html")
 	if err != nil {
 		panic(err)
@@ -36,6 +43,274 @@ func TestParse(t *testing.T) { // dirty, but simple
 	_ = doc
 }
 
+func TestExamples(t *testing.T) {
+	c := newTestingContext()
+
+	if err := patcher.LoadFile("../patcher/patches.json"); err != nil {
+		t.Fatal(err)
+	}
+
+	Convey("Parse keeps examples of classes and fields", t, func() {
+		gqDoc, err := OpenGoQueryDoc("./methods.html")
+		So(err, ShouldBeNil)
+		doc, err := Parse(c, gqDoc)
+		So(err, ShouldBeNil)
+
+		var res Resource
+		for _, r := range doc.Resources {
+			if r.ID == "tournament-provider" {
+				res = r
+			}
+		}
+		params := res.Definitions["TournamentCodeParameters"]
+		So(params.Example, ShouldStartWith, `{"teamSize":0,"allowedSummonerIds":{"participants":[0]}`)
+		So(params.Fields[0].Example, ShouldEqual, `{"participants":[0]}`)
+		So(res.Definitions["SummonerIdParams"].Example, ShouldEqual, `{"participants":[0]}`)
+
+		Convey("Legal values are examples of string fields", func() {
+			So(LegalValues("The map type of the game. (Legal values: SUMMONERS_RIFT, TWISTED_TREELINE)"),
+				ShouldResemble, []string{"SUMMONERS_RIFT", "TWISTED_TREELINE"})
+			So(NewField("mapType", types.Typ[types.String], "(Legal values: SUMMONERS_RIFT, TWISTED_TREELINE)").Example,
+				ShouldEqual, `"SUMMONERS_RIFT"`)
+		})
+	})
+}
+
+func TestParseErrors(t *testing.T) {
+	c := newTestingContext()
+
+	if err := patcher.LoadFile("../patcher/patches.json"); err != nil {
+		t.Fatal(err)
+	}
+
+	Convey("Parse collects every error with its location", t, func() {
+		gqDoc, err := OpenGoQueryDoc("./methods.html")
+		So(err, ShouldBeNil)
+		gqDoc.Find("#1077_3717").RemoveClass("get")
+		gqDoc.Find("#1077_3716 .api_block h4").First().SetText("Unknown Block")
+
+		doc, err := Parse(c, gqDoc)
+		So(err, ShouldHaveSameTypeAs, ParseErrors{})
+		errs := err.(ParseErrors)
+		So(errs, ShouldHaveLength, 2)
+
+		So(errs[0].Resource, ShouldEqual, "champion")
+		So(errs[0].Operation, ShouldBeEmpty)
+		So(errs[0].CSSPath, ShouldEqual, "li#1077_3717.operation")
+		So(errs[0].Err.Error(), ShouldEqual, "unknown operation method")
+
+		So(errs[1].Resource, ShouldEqual, "champion")
+		So(errs[1].Operation, ShouldEqual, "GET /api/lol/{region}/v1.2/champion/{id}")
+		So(errs[1].Block, ShouldEqual, "Unknown Block")
+		So(errs[1].CSSPath, ShouldEqual, "div#3716_content.content > div.api_block:nth-of-type(1)")
+		So(errs[1].Snippet, ShouldStartWith, `<div class="api_block">`)
+		So(strings.Count(errs[1].Snippet, "\n"), ShouldBeLessThanOrEqualTo, 8)
+		So(err.Error(), ShouldContainSubstring, `block "Unknown Block"`)
+
+		Convey("Messages wrapping a ParseError are kept", func() {
+			s := htmlutil.Wrap(mustParse(`<table><tr><td>a</td></tr></table>`).Find("td"))
+			wrapped := errors.Wrapf(errors.Wrap(s.Errorf("unknown type"), "failed to parse class \"Game\" \n"), "failed to parse body\n")
+			pe := locate(c, s, wrapped)
+			So(pe.Err.Error(), ShouldEqual, `failed to parse body: failed to parse class "Game": unknown type`)
+			So(errors.Cause(pe.Err).Error(), ShouldEqual, "unknown type")
+			So(pe.CSSPath, ShouldEqual, "html > body > table > tbody > tr > td")
+		})
+
+		Convey("Messages containing \": \" are kept as is", func() {
+			s := htmlutil.Wrap(mustParse(`<table><tr><td>a</td></tr></table>`).Find("td"))
+			cause := s.Errorf("unknown type: map")
+			wrapped := errors.Wrap(errors.WithStack(errors.Wrap(cause, `separator ": : " of field id`)), "class: Game")
+			pe := locate(c, s, wrapped)
+			So(pe.Err.Error(), ShouldEqual, `class: Game: separator ": : " of field id: unknown type: map`)
+			So(errors.Cause(pe.Err).Error(), ShouldEqual, "unknown type: map")
+		})
+
+		// other resources and operations are parsed.
+		So(len(doc.Resources), ShouldBeGreaterThan, 10)
+		So(doc.Resources[0].ID, ShouldEqual, "champion")
+		So(doc.Resources[0].Operations, ShouldHaveLength, 1)
+	})
+}
+
+func TestDiff(t *testing.T) {
+	Convey("Diff", t, func() {
+		old := &Doc{Resources: []Resource{
+			{ID: "a", Version: "v1.0", Regions: []string{"NA"}},
+			{ID: "b", Version: "v1.0"},
+		}}
+		new := &Doc{Resources: []Resource{
+			{ID: "a", Version: "v1.1", Regions: []string{"NA", "KR"}},
+			{ID: "c", Version: "v2.0"},
+		}}
+
+		So(Diff(old, old), ShouldBeEmpty)
+		So(Diff(old, new), ShouldResemble, []Change{
+			{Kind: Changed, Resource: "a", What: "version", Old: "v1.0", New: "v1.1"},
+			{Kind: Added, Resource: "a", What: "region KR"},
+			{Kind: Removed, Resource: "b", What: "resource v1.0"},
+			{Kind: Added, Resource: "c", What: "resource v2.0"},
+		})
+
+		Convey("Reports changes of operations, parameters, bodies and classes", func() {
+			str, i32, i64 := types.Typ[types.String], types.Typ[types.Int32], types.Typ[types.Int64]
+			summoner := func(version string, changed bool) Resource {
+				res := &Resource{ID: "summoner", Version: version}
+				get := &Operation{
+					HTTPMethod:     "GET",
+					RequestPath:    "/api/lol/{region}/" + version + "/summoner/{summonerIds}",
+					OrigReturnType: i32,
+					PathParams:     Parameters{{Name: "summonerIds", Type: str, Required: true}},
+					QueryParams:    Parameters{{Name: "type", Type: str, Enum: []string{"A", "B"}}},
+					ResponseErrors: []*ResponseError{{Code: 404}},
+				}
+				post := &Operation{HTTPMethod: "POST", RequestPath: "/" + version + "/code", Body: &Parameter{Name: "body", Type: str}}
+				put := &Operation{HTTPMethod: "PUT", RequestPath: "/" + version + "/code"}
+				fields := []Field{NewField("id", i32, ""), NewField("name", str, "")}
+				if changed {
+					get.OrigReturnType = i64
+					get.PathParams = Parameters{{Name: "summonerIds", Type: types.NewSlice(i64), Required: true, MaxItems: 40}}
+					get.QueryParams = Parameters{
+						{Name: "type", Type: str, Required: true, Enum: []string{"A", "C"}},
+						{Name: "limit", Type: i32},
+					}
+					get.ResponseErrors = append(get.ResponseErrors, &ResponseError{Code: 429})
+					post.Body.Type = i32
+					put.Body = &Parameter{Name: "body", Type: str}
+					fields = []Field{NewField("id", i64, ""), NewField("level", i32, "")}
+				}
+				for _, op := range []*Operation{get, post, put} {
+					res.AddOperation(op)
+				}
+				res.AddDefinition(Schema{OrigName: "Summoner", Fields: fields})
+				return *res
+			}
+
+			old := &Doc{Resources: []Resource{summoner("v1.3", false)}}
+			new := &Doc{Resources: []Resource{summoner("v1.4", true)}}
+			get := "operation GET /api/lol/{region}/{version}/summoner/{summonerIds} "
+			So(Diff(old, new), ShouldResemble, []Change{
+				{Kind: Changed, Resource: "summoner", What: "version", Old: "v1.3", New: "v1.4"},
+				{Kind: Changed, Resource: "summoner", What: get + "return value type", Old: "int32", New: "int64"},
+				{Kind: Changed, Resource: "summoner", What: get + "path parameter summonerIds type", Old: "string", New: "[]int64"},
+				{Kind: Changed, Resource: "summoner", What: get + "path parameter summonerIds max items", Old: "0", New: "40"},
+				{Kind: Added, Resource: "summoner", What: get + "query parameter limit"},
+				{Kind: Changed, Resource: "summoner", What: get + "query parameter type required", Old: "false", New: "true"},
+				{Kind: Removed, Resource: "summoner", What: get + "query parameter type value B"},
+				{Kind: Added, Resource: "summoner", What: get + "query parameter type value C"},
+				{Kind: Added, Resource: "summoner", What: get + "response error 429"},
+				{Kind: Changed, Resource: "summoner", What: "operation POST /{version}/code body type", Old: "string", New: "int32"},
+				{Kind: Added, Resource: "summoner", What: "operation PUT /{version}/code body string"},
+				{Kind: Changed, Resource: "summoner", What: "class Summoner field id type", Old: "int32", New: "int64"},
+				{Kind: Added, Resource: "summoner", What: "class Summoner field level int32"},
+				{Kind: Removed, Resource: "summoner", What: "class Summoner field name string"},
+			})
+		})
+	})
+}
+
+func TestDedup(t *testing.T) {
+	p, err := patcher.Load(strings.NewReader(`{
+		"version": 1,
+		"resources": {
+			"a": {"classes": {"Observer": {"name": "AObserver"}, "Game": {"name": "AGame"}}},
+			"b": {"classes": {"Observer": {"name": "BObserver"}, "Game": {"name": "BGame"}}},
+			"c": {"classes": {"Observer": {"name": "CObserver", "distinct": true}}}
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	patcher.Use(p)
+
+	Convey("Dedup", t, func() {
+		doc := &Doc{}
+		for _, id := range []string{"a", "b", "c"} {
+			res := Resource{ID: id}
+			res.AddDefinition(Schema{
+				OrigName:   "Observer",
+				StructName: patcher.StructName(id, "Observer"),
+				Fields:     []Field{NewField("encryptionKey", types.Typ[types.String], "key of "+id)},
+			})
+			if id != "c" {
+				res.AddDefinition(Schema{
+					OrigName:   "Game",
+					StructName: patcher.StructName(id, "Game"),
+					Fields:     []Field{NewField("observers", patcher.ClassType(id, "Observer"), "")},
+				})
+				res.AddOperation(&Operation{MethodName: "Game" + id, OrigReturnType: types.NewSlice(patcher.ClassType(id, "Game"))})
+			}
+			doc.Resources = append(doc.Resources, res)
+		}
+
+		So(Dedup(doc), ShouldResemble, []string{"Game", "Observer"})
+
+		a, c := doc.Resources[0], doc.Resources[2]
+		So(a.Definitions["Observer"].SharedName, ShouldEqual, "Observer")
+		So(a.Definitions["Game"].Fields[0].Type.String(), ShouldEqual, "*Observer")
+		So(a.Operations[0].OrigReturnType.String(), ShouldEqual, "[]*Game")
+		So(doc.Resources[1].Definitions["Observer"].SharedName, ShouldEqual, "Observer")
+		So(c.Definitions["Observer"].SharedName, ShouldBeEmpty)
+
+		Convey("Classes without fields are not merged", func() {
+			doc := &Doc{}
+			for _, id := range []string{"A", "B"} {
+				res := Resource{ID: id}
+				res.AddDefinition(Schema{OrigName: "Empty", StructName: id + "Empty"})
+				doc.Resources = append(doc.Resources, res)
+			}
+			So(Dedup(doc), ShouldBeNil)
+			So(doc.Resources[0].Definitions["Empty"].SharedName, ShouldBeEmpty)
+			So(doc.Resources[1].Definitions["Empty"].SharedName, ShouldBeEmpty)
+		})
+	})
+}
+
+func TestSuffixVersions(t *testing.T) {
+	Convey("SuffixVersions", t, func() {
+		doc := &Doc{}
+		for _, version := range []string{"v1.10", "v2.2", "v1.9"} {
+			res := Resource{ID: "a", Version: version}
+			res.AddDefinition(Schema{
+				OrigName:   "GameDto",
+				StructName: "Game",
+				Fields:     []Field{NewField("id", types.Typ[types.Int64], "")},
+			})
+			game := types.NewNamed(types.NewTypeName(token.NoPos, nil, "Game", nil), nil, nil)
+			res.AddOperation(&Operation{MethodName: "Games", OrigReturnType: types.NewSlice(types.NewPointer(game))})
+			doc.Resources = append(doc.Resources, res)
+		}
+		So(SuffixVersions(doc), ShouldBeNil)
+
+		v110, v22, v19 := doc.Resources[0], doc.Resources[1], doc.Resources[2]
+		So(v22.Suffix, ShouldBeEmpty)
+		So(v22.Operations[0].MethodName, ShouldEqual, "Games")
+		So(v110.Suffix, ShouldEqual, "V1_10")
+		So(v110.Definitions["GameDto"].StructName, ShouldEqual, "GameV1_10")
+		So(v110.Operations[0].MethodName, ShouldEqual, "GamesV1_10")
+		So(v110.Operations[0].OrigReturnType.String(), ShouldEqual, "[]*GameV1_10")
+		So(v19.Operations[0].MethodName, ShouldEqual, "GamesV1_9")
+
+		Convey("Dedup shares the newest name", func() {
+			So(Dedup(doc), ShouldResemble, []string{"Game"})
+			So(v19.Definitions["GameDto"].SharedName, ShouldEqual, "Game")
+			So(v19.Operations[0].OrigReturnType.String(), ShouldEqual, "[]*Game")
+		})
+
+		Convey("Dedup shares the name of the newest version, however long it is", func() {
+			latest := v22.Definitions["GameDto"]
+			latest.StructName = "LatestGame" // e.g. renamed by a patch
+			v22.Definitions["GameDto"] = latest
+			So(Dedup(doc), ShouldResemble, []string{"LatestGame"})
+			So(v110.Definitions["GameDto"].SharedName, ShouldEqual, "LatestGame")
+		})
+
+		Convey("A version defined twice is an error", func() {
+			doc.Resources = append(doc.Resources, Resource{ID: "a", Version: "v1.9"})
+			So(SuffixVersions(doc), ShouldNotBeNil)
+		})
+	})
+}
+
 func TestParsingUtils(t *testing.T) {
 	Convey("consumeSelect", t, func() {
 		s := htmlutil.Wrap(mustParse(`<select class="select any class" id="dnjaf" name="virtual" >
@@ -63,6 +338,17 @@ func TestParsingUtils(t *testing.T) {
 		So(parseRegions("[BR, EUNE, EUW]"), ShouldResemble, []string{"BR", "EUNE", "EUW"})
 	})
 
+	Convey("parseMaxItems", t, func() {
+		So(parseMaxItems("Comma-separated list of summoner IDs. Maximum allowed at once is 10."), ShouldEqual, 10)
+		So(parseMaxItems("The ID of the summoner."), ShouldEqual, 0)
+	})
+
+	Convey("parseRequiredTogether", t, func() {
+		So(parseRequiredTogether("If either of the beginTime or endTime parameters is set, they must both be set, although there is no maximum limit on their range."),
+			ShouldResemble, [][]string{{"beginTime", "endTime"}})
+		So(parseRequiredTogether("Not all matches have timeline data."), ShouldBeNil)
+	})
+
 	Convey("parseResourceIDVersion", t, func() {
 		datas := []struct {
 			src, id, ver string
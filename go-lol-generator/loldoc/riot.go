@@ -1,6 +1,7 @@
 package loldoc
 
 import (
+	"os"
 	"strconv"
 	"strings"
 
@@ -18,8 +19,38 @@ func NewGoQueryDoc() (*goquery.Document, error) {
 	return goquery.NewDocument(docURL)
 }
 
+// OpenGoQueryDoc loads a document from url (http:// or https://) or a html file.
+func OpenGoQueryDoc(src string) (*goquery.Document, error) {
+	if strings.HasPrefix(src, "http://") || strings.HasPrefix(src, "https://") {
+		return goquery.NewDocument(src)
+	}
+
+	f, err := os.Open(src)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return goquery.NewDocumentFromReader(f)
+}
+
+// Filter reports whether a resource should be parsed.
+type Filter func(resID string) bool
+
+// Parse parses every resource in d.
 func Parse(c context.Context, d *goquery.Document) (*Doc, error) {
-	doc := &Doc{}
+	return ParseFiltered(c, d, nil)
+}
+
+// ParseFiltered parses resources accepted by filter. nil filter accepts every resource.
+//
+// Errors of resources, operations and api blocks are collected in one pass, and
+// returned as ParseErrors with doc, which lacks the parts failed to parse.
+// Otherwise, if patches are missing, the error is patcher.ErrPatchesRequired, and doc is
+// returned as well with original names in place of missing ones.
+func ParseFiltered(c context.Context, d *goquery.Document, filter Filter) (doc *Doc, err error) {
+	defer recoverParseError(&err)
+	doc = &Doc{}
+	c = withErrors(c)
 
 	s := d.ChildrenFiltered("html").
 		ChildrenFiltered("body").
@@ -33,9 +64,10 @@ func Parse(c context.Context, d *goquery.Document) (*Doc, error) {
 		ul := s.Children().First().Ensure("ul#resources")
 
 		for _, li := range ul.Children() {
-			res, err := parseResource(c, li)
+			res, err := parseResource(c, li, filter)
 			if err != nil {
-				return nil, errors.Wrapf(err, "failed to parse resource %q \n", res.ID)
+				addError(c, li, err)
+				continue
 			} else if res.ID == "" { // skip
 				continue
 			}
@@ -44,14 +76,29 @@ func Parse(c context.Context, d *goquery.Document) (*Doc, error) {
 		}
 	}
 
+	if errs := parseErrors(c); len(errs) != 0 {
+		return doc, errs
+	}
+
+	// report every missing patch at once.
+	if missing := patcher.Missing(); len(missing) != 0 {
+		return doc, missing
+	}
+
 	return doc, nil
 }
 
-func parseResource(c context.Context, s htmlutil.Sel) (Resource, error) {
+func parseResource(c context.Context, s htmlutil.Sel, filter Filter) (res Resource, err error) {
+	defer func() {
+		if err != nil {
+			err = locate(c, s, err)
+		}
+	}()
+	defer recoverParseError(&err)
 	s.Ensure("li.resource")
 	s.ChildrenFiltered(".heading").MustBeSingle().
 		ChildrenFiltered("ul.options").MustBeSingle().Remove()
-	res := Resource{
+	res = Resource{
 		Definitions: make(map[string]Schema),
 	}
 
@@ -67,9 +114,8 @@ func parseResource(c context.Context, s htmlutil.Sel) (Resource, error) {
 		res.Regions = parseRegions(regionText)
 	}
 
-	// this client doesn't support tournament api.
-	if res.ID == "tournament-provider" {
-		logging.Warningf(c, "skipping resource %q", res.ID)
+	if filter != nil && !filter(res.ID) {
+		logging.Infof(c, "skipping resource %q", res.ID)
 		return Resource{}, nil
 	}
 	c = withResID(c, res.ID)
@@ -77,7 +123,7 @@ func parseResource(c context.Context, s htmlutil.Sel) (Resource, error) {
 	for _, s := range s.ChildrenFiltered("ul.endpoints").Children() {
 		ops, err := parseEndpoint(c, &res, s)
 		if err != nil {
-			return res, errors.Wrap(err, "parse endpoint")
+			addError(c, s, err)
 		}
 		res.Operations = append(res.Operations, ops...)
 	}
@@ -87,14 +133,16 @@ func parseResource(c context.Context, s htmlutil.Sel) (Resource, error) {
 	return res, nil
 }
 
-func parseEndpoint(c context.Context, res *Resource, s htmlutil.Sel) ([]*Operation, error) {
+// parseEndpoint returns operations parsed successfully. Errors of operations are recorded by addError.
+func parseEndpoint(c context.Context, res *Resource, s htmlutil.Sel) (ops []*Operation, err error) {
+	defer recoverParseError(&err)
 	s.Ensure("li.endpoint")
-	var ops []*Operation
 
 	for _, s := range s.ChildrenFiltered("ul.operations").Children() {
 		op, err := parseOperation(c, res, s)
 		if err != nil {
-			return nil, err
+			addError(c, s, err)
+			continue
 		}
 		ops = append(ops, op)
 	}
@@ -105,12 +153,29 @@ func parseEndpoint(c context.Context, res *Resource, s htmlutil.Sel) ([]*Operati
 // ".heading > .http_method": HTTP method to use
 // ".heading > .path": HTTP request path
 // ".heading > .options": description
-func parseOperation(c context.Context, res *Resource, s htmlutil.Sel) (*Operation, error) {
+func parseOperation(c context.Context, res *Resource, s htmlutil.Sel) (_ *Operation, err error) {
+	defer func() {
+		if err != nil {
+			err = locate(c, s, err)
+		}
+	}()
+	defer recoverParseError(&err)
 	s.Ensure("li.operation")
 
 	op := Operation{}
 	op.res = res
 
+	switch {
+	case s.HasClass("get"):
+		op.HTTPMethod = "GET"
+	case s.HasClass("post"):
+		op.HTTPMethod = "POST"
+	case s.HasClass("put"):
+		op.HTTPMethod = "PUT"
+	default:
+		return nil, s.WithDump(errors.New("unknown operation method"))
+	}
+
 	{ // parse: .heading
 		heading := s.ChildrenFiltered("div.heading").MustBeSingle()
 
@@ -125,10 +190,15 @@ func parseOperation(c context.Context, res *Resource, s htmlutil.Sel) (*Operatio
 
 		heading.Remove() // remove: .heading
 	}
+	c = withOperation(c, op.HTTPMethod+" "+op.RequestPath)
+
 	{ // handle special path parameters like 'region' and 'platformId'
 		for _, keyword := range []string{"region", "platformId"} {
 			if strings.Contains(op.RequestPath, "{"+keyword+"}") {
-				typ, _ := patcher.Type(res.ID, "Region")
+				typ, err := patcher.Type(res.ID, "Region")
+				if err != nil {
+					return nil, err
+				}
 				op.PathParams = append(op.PathParams, Parameter{
 					Name: keyword,
 					Type: typ,
@@ -136,27 +206,16 @@ func parseOperation(c context.Context, res *Resource, s htmlutil.Sel) (*Operatio
 			}
 		}
 	}
-	patch, err := patcher.ForOperation(res.ID, op.RequestPath)
-	if err != nil {
-		return nil, err
-	}
+	patch := patcher.ForOperation(res.ID, op.HTTPMethod, op.RequestPath)
 	op.MethodName = patch.Name
 	op.OverridedMapKey = patch.MapKey
 
-	switch {
-	case s.HasClass("get"):
-		op.HTTPMethod = "GET"
-	case s.HasClass("post"):
-		op.HTTPMethod = "POST"
-	case s.HasClass("put"):
-		op.HTTPMethod = "PUT"
-	default:
-		return nil, s.WithDump(errors.New("unknown operation method"))
-	}
-
+	// errors of blocks are recorded, so that errors of following blocks are found as well.
 	for _, block := range s.Find(".content .api_block") {
-		if err := parseAPIBlock(c, res, &op, block); err != nil {
-			return nil, errors.Wrap(err, "parse api block")
+		for _, block := range splitAPIBlock(block) {
+			if err := parseAPIBlock(c, res, &op, block); err != nil {
+				addError(c, block, err)
+			}
 		}
 	}
 
@@ -164,6 +223,12 @@ func parseOperation(c context.Context, res *Resource, s htmlutil.Sel) (*Operatio
 }
 
 func parseAPIBlock(c context.Context, res *Resource, op *Operation, s htmlutil.Sel) (err error) {
+	defer func() {
+		if err != nil {
+			err = locate(c, s, err)
+		}
+	}()
+	defer recoverParseError(&err)
 	s.Ensure(".api_block")
 
 	if len(s.Children()) == 0 { // status api has an empty .api_block
@@ -171,6 +236,7 @@ func parseAPIBlock(c context.Context, res *Resource, op *Operation, s htmlutil.S
 	}
 
 	blockType := s.Children().First().Ensure("h4").EatText() // remove: h4
+	c = withBlock(c, blockType)
 
 	switch blockType {
 	case "Response Classes":
@@ -230,6 +296,14 @@ func parseAPIBlock(c context.Context, res *Resource, op *Operation, s htmlutil.S
 		op.PathParams = append(op.PathParams, params...)
 		return nil
 
+	case "Body":
+		param, err := parseBody(c, res, s)
+		if err != nil {
+			return errors.Wrap(err, "failed to parse body\n")
+		}
+		op.Body = param
+		return nil
+
 	case "Select Region to Execute Against": // ignore
 		return nil
 
@@ -237,6 +311,7 @@ func parseAPIBlock(c context.Context, res *Resource, op *Operation, s htmlutil.S
 		// has single <p> node as a child
 		op.ImplNotes = s.
 			Children().MustBeSingle().Ensure("p").Text()
+		op.RequiredTogether = parseRequiredTogether(op.ImplNotes)
 		return nil
 
 	case "Rate Limit Notes":
@@ -309,12 +384,28 @@ func parsePathParams(c context.Context, s htmlutil.Sel) (Parameters, error) {
 			param.Type = typ
 			td.Remove()
 		}
+		if err := parseConstraints(&param, tr); err != nil {
+			return nil, err
+		}
 		params = append(params, param)
 		logging.Debugf(c, "path param %q", param.Name)
 	}
 	return params, nil
 }
 
+// parseConstraints reads allowed values from the rest of tr, and maximum length from description.
+func parseConstraints(param *Parameter, tr htmlutil.Sel) (err error) {
+	param.Enum, param.Multiple, err = consumeParamEnum(tr)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse allowed values of %q\n", param.Name)
+	}
+	if len(param.Enum) == 0 {
+		param.Enum = patcher.ParamEnum(param.Name)
+	}
+	param.MaxItems = parseMaxItems(param.Description)
+	return nil
+}
+
 // <table> query params </table>
 // <select> REGION </select>
 func parseQueryParams(c context.Context, s htmlutil.Sel) (Parameters, error) {
@@ -357,6 +448,9 @@ func parseQueryParams(c context.Context, s htmlutil.Sel) (Parameters, error) {
 			param.Type = typ
 			td.Remove() // remove: td
 		}
+		if err := parseConstraints(&param, tr); err != nil {
+			return nil, err
+		}
 
 		params = append(params, param)
 	}
@@ -367,6 +461,62 @@ func parseQueryParams(c context.Context, s htmlutil.Sel) (Parameters, error) {
 	return params, nil
 }
 
+// <table> body </table>
+// <div class="block response_body"> $class </div>...
+func parseBody(c context.Context, res *Resource, s htmlutil.Sel) (*Parameter, error) {
+	s.Ensure(".api_block")
+
+	table := s.Children().First().Ensure("table")
+	tr := table.ChildrenFiltered("tbody.operation-params").MustBeSingle().
+		Children().MustBeSingle().Ensure("tr")
+	if cnt := len(tr.Children()); cnt != 3 {
+		return nil, tr.WithDump(errors.Errorf("body: expected 3 columns, but got %d", cnt))
+	}
+
+	param := &Parameter{Required: true}
+	var class, example string // original name of the class of body, and its documented example
+	{
+		textarea := tr.Children().First().Children().MustBeSingle().Ensure("textarea")
+		param.Name, _ = textarea.Attr("name")
+	}
+
+	param.Description = tr.Children().Last().Ensure("td").EatText() // remove: td
+
+	{
+		// <span.model-signature> <div> <div> <div.signature-container> $class <br> <br> <div.snippet> ...
+		container := tr.Children().Last().Find("div.signature-container").MustBeSingle()
+		var err error
+		if example, err = consumeExample(container); err != nil { // remove: pre
+			return nil, err
+		}
+		container.Selection.Children().Remove() // remove: br, div.snippet
+
+		class = container.Text()
+		typ, err := patcher.Type(resID(c), class)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse type of body %q\n", param.Name)
+		}
+		param.Type = typ
+	}
+	table.Remove() // remove: table
+
+	// request classes
+	for _, reqBody := range s.ChildrenFiltered(".block.response_body") {
+		cls, err := parseResponseClass(c, reqBody)
+		if err != nil {
+			return nil, err
+		}
+		cls.res = res
+		res.Definitions[cls.OrigName] = cls
+	}
+	if example != "" {
+		applyExample(res, class, example)
+	}
+
+	logging.Debugf(c, "body %q", param.Name)
+	return param, nil
+}
+
 // <b>$class</b> - $description
 // <br>
 // <table> $fields... </table>
@@ -380,11 +530,7 @@ func parseResponseClass(c context.Context, s htmlutil.Sel) (cls Schema, err erro
 	}()
 
 	cls.OrigName = s.Children().First().Ensure("b").EatText() // remove: b
-	cp, err := patcher.ForClass(resID(c), cls.OrigName)
-	if err != nil {
-		return cls, err
-	}
-	cls.StructName = cp.Name
+	cls.StructName = patcher.StructName(resID(c), cls.OrigName)
 	c = logging.SetField(c, "class", cls.StructName)
 
 	s.Children().First().Ensure("br").Remove() // remove: <br>
@@ -2,6 +2,9 @@ package loldoc
 
 import (
 	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/PuerkitoBio/goquery"
@@ -50,6 +53,46 @@ func consumeSelect(s htmlutil.Sel) (vals map[string]string, err error) {
 	return
 }
 
+// consumeParamEnum reads allowed values of a parameter from <select>s in s.
+// enum is nil if s does not have a <select>.
+func consumeParamEnum(s htmlutil.Sel) (enum []string, multiple bool, err error) {
+	for _, sel := range s.Find("select") {
+		_, multiple = sel.Attr("multiple")
+		vals, err := consumeSelect(sel)
+		if err != nil {
+			return nil, false, err
+		}
+		for val := range vals {
+			enum = append(enum, val)
+		}
+	}
+	sort.Strings(enum)
+	return enum, multiple, nil
+}
+
+var maxItemsRegexp = regexp.MustCompile(`Maximum allowed at once is (\d+)\.`)
+
+// parseMaxItems returns 0 if description does not limit length of a list.
+func parseMaxItems(description string) int {
+	m := maxItemsRegexp.FindStringSubmatch(description)
+	if m == nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(m[1])
+	return n
+}
+
+var requiredTogetherRegexp = regexp.MustCompile(`If either of the (\w+) or (\w+) parameters is set, they must both be set`)
+
+// parseRequiredTogether finds parameters which must be set together in implementation notes.
+func parseRequiredTogether(notes string) [][]string {
+	var groups [][]string
+	for _, m := range requiredTogetherRegexp.FindAllStringSubmatch(notes, -1) {
+		groups = append(groups, []string{m[1], m[2]})
+	}
+	return groups
+}
+
 func consumeSimpleTable(table htmlutil.Sel) ([]map[string]string, error) {
 	table.Ensure("table")
 
@@ -106,6 +149,24 @@ func consumeTableRow(tr htmlutil.Sel, isHead bool) []string {
 	return vals
 }
 
+// splitAPIBlock splits an .api_block which contains multiple <h4> sections
+// (e.g. Path Parameters, Query Parameters and Body) into one .api_block per section.
+func splitAPIBlock(s htmlutil.Sel) htmlutil.Sels {
+	s.Ensure(".api_block")
+
+	headings := s.Selection.ChildrenFiltered("h4")
+	if headings.Length() <= 1 {
+		return htmlutil.Sels{s}
+	}
+
+	var blocks htmlutil.Sels
+	headings.Each(func(_ int, h4 *goquery.Selection) {
+		h4.AddSelection(h4.NextUntil("h4")).WrapAllHtml(`<div class="api_block"></div>`)
+		blocks = append(blocks, htmlutil.Wrap(h4.Parent()))
+	})
+	return blocks
+}
+
 func removeIfUseless(_ int, s *goquery.Selection) {
 	if isUseless(s) {
 
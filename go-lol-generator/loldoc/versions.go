@@ -0,0 +1,115 @@
+package loldoc
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// SuffixVersions lets several versions of a resource be generated side by side.
+//
+// The newest version of a resource keeps its names. Methods, classes and the interface of
+// older versions get VersionSuffix of the version (e.g. SummonersV1_3 and SummonerV1_3),
+// and types referring to their classes are rewritten. Run Dedup after it, so that classes
+// which did not change between versions are generated once.
+// It returns an error if the same version of a resource is defined twice.
+func SuffixVersions(doc *Doc) error {
+	byID := make(map[string][]*Resource)
+	var ids []string
+	for i := range doc.Resources {
+		res := &doc.Resources[i]
+		if _, ok := byID[res.ID]; !ok {
+			ids = append(ids, res.ID)
+		}
+		byID[res.ID] = append(byID[res.ID], res)
+	}
+
+	for _, id := range ids {
+		versions := byID[id]
+		if len(versions) < 2 {
+			continue
+		}
+		sort.SliceStable(versions, func(i, j int) bool {
+			return compareVersions(versions[i].Version, versions[j].Version) > 0
+		})
+		for i, res := range versions[1:] {
+			if versions[i].Version == res.Version {
+				return errors.Errorf("loldoc: version %s of resource %q is defined twice", res.Version, id)
+			}
+			suffixResource(res)
+		}
+	}
+	return nil
+}
+
+// VersionSuffix returns the suffix of names of an older version (e.g. "V1_3" for "v1.3").
+func VersionSuffix(version string) string {
+	v := strings.TrimPrefix(strings.TrimPrefix(version, "v"), "V")
+	return "V" + strings.Replace(v, ".", "_", -1)
+}
+
+func suffixResource(res *Resource) {
+	res.Suffix = VersionSuffix(res.Version)
+
+	renames := make(map[string]string)
+	for key, s := range res.Definitions {
+		renames[s.StructName] = s.StructName + res.Suffix
+		s.StructName += res.Suffix
+		res.Definitions[key] = s
+	}
+	for key, s := range res.Definitions {
+		for j, f := range s.Fields {
+			s.Fields[j].Type = renameTypes(f.Type, renames)
+		}
+		res.Definitions[key] = s
+	}
+	for _, op := range res.Operations {
+		op.MethodName += res.Suffix
+		op.OrigReturnType = renameTypes(op.OrigReturnType, renames)
+		if op.Body != nil {
+			op.Body.Type = renameTypes(op.Body.Type, renames)
+		}
+	}
+}
+
+// compareVersions compares versions like "v1.4" and "v2.2" by number.
+// It returns a positive number if a is newer than b.
+func compareVersions(a, b string) int {
+	pa := strings.Split(strings.TrimPrefix(a, "v"), ".")
+	pb := strings.Split(strings.TrimPrefix(b, "v"), ".")
+	for i := 0; i < len(pa) || i < len(pb); i++ {
+		var na, nb int
+		if i < len(pa) {
+			na, _ = strconv.Atoi(pa[i])
+		}
+		if i < len(pb) {
+			nb, _ = strconv.Atoi(pb[i])
+		}
+		if na != nb {
+			return na - nb
+		}
+	}
+	return strings.Compare(a, b)
+}
+
+// newestOf returns the struct name of the class of the newest version, if group is classes of
+// versions of one resource. versions has the version of the resource of each class.
+// It returns false if the newest version has several classes in group.
+func newestOf(group []*Schema, versions map[*Schema]string) (string, bool) {
+	newest := group[0]
+	unique := true
+	for _, s := range group[1:] {
+		if s.ResID() != group[0].ResID() {
+			return "", false
+		}
+		switch c := compareVersions(versions[s], versions[newest]); {
+		case c > 0:
+			newest, unique = s, true
+		case c == 0:
+			unique = false
+		}
+	}
+	return newest.StructName, unique
+}
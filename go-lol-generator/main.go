@@ -2,6 +2,8 @@ package main
 
 import (
 	"bytes"
+	"encoding/json"
+	"flag"
 	"fmt"
 	"go/ast"
 	"go/format"
@@ -9,13 +11,18 @@ import (
 	"io/ioutil"
 	"log"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"unicode"
 
 	"github.com/kdy1997/go-lol/go-lol-generator/loldoc"
+	"github.com/kdy1997/go-lol/go-lol-generator/openapi"
+	"github.com/kdy1997/go-lol/go-lol-generator/patcher"
+	"github.com/kdy1997/go-lol/go-lol-generator/typescript"
 	"github.com/luci/luci-go/common/logging"
 	"github.com/luci/luci-go/common/logging/gologger"
+	"github.com/pkg/errors"
 	"golang.org/x/net/context"
 )
 
@@ -28,51 +35,308 @@ func formatFile(filename string, src []byte) ([]byte, error) {
 	return data, nil
 }
 
-const targetFile = "lol.generated.go"
+var (
+	inputPath   = flag.String("in", "go-lol-generator/loldoc/methods.html", "html file, OpenAPI json file, directory containing them, or url of riot api document")
+	outputPath  = flag.String("out", "", `output file ("-" for stdout). defaults to lol.generated.go for go, and stdout for other formats`)
+	outFormat   = flag.String("format", "go", `output format: "go", "openapi" or "typescript"`)
+	pkgNameFlag = flag.String("pkg", "lol", "package name of generated file")
+	patchesFile = flag.String("patches", "go-lol-generator/patcher/patches.json", "path to patch manifest")
+	include     = flag.String("include", "", "comma-separated resource ids to generate (default: all)")
+	exclude     = flag.String("exclude", "", "comma-separated resource ids not to generate")
+	check       = flag.Bool("check", false, "do not write output, but exit with non-zero status if it differs")
+	dedup       = flag.Bool("dedup", true, "merge identical classes of different resources into shared types")
+	codecPath   = flag.String("codec", "", "output file of generated json methods of response classes (default: not generated)")
+	fixtures    = flag.String("fixtures", "", "output test file of sample json of response classes (default: not generated)")
+)
 
 func main() {
 	c := context.Background()
 	c = gologger.StdConfig.Use(c)
 
-	gqDoc, err := loldoc.NewGoQueryDoc()
+	var err error
+	switch {
+	case len(os.Args) > 1 && os.Args[1] == "diff":
+		err = runDiff(c, os.Args[2:])
+	default:
+		flag.Parse()
+		err = generate(c)
+	}
+
 	if err != nil {
-		logging.Errorf(c, "failed to create goquery document: %+v", err)
-		return
+		logging.Errorf(c, "%+v", err)
+		os.Exit(1)
+	}
+}
+
+func generate(c context.Context) error {
+	if err := patcher.LoadFile(*patchesFile); err != nil {
+		return errors.Wrap(err, "failed to load patches")
 	}
 
-	doc, err := loldoc.Parse(c, gqDoc)
+	doc, err := loadDoc(c, *inputPath, resourceFilter(*include, *exclude))
 	if err != nil {
-		logging.Errorf(c, "failed to parse lol api doc: %+v", err)
-		return
+		return err
+	}
+
+	out := *outputPath
+	var src []byte
+	switch *outFormat {
+	case "go":
+		if out == "" {
+			out = "lol.generated.go"
+		}
+		files, err := generateGo(c, doc, goOptions{
+			pkgName:  *pkgNameFlag,
+			dedup:    *dedup,
+			codec:    *codecPath != "",
+			fixtures: *fixtures != "",
+		})
+		if err != nil {
+			return err
+		}
+		src = files.generated
+		if *codecPath != "" {
+			if err := writeOutput(*codecPath, files.codec); err != nil {
+				return err
+			}
+		}
+		if *fixtures != "" {
+			if err := writeOutput(*fixtures, files.fixtures); err != nil {
+				return err
+			}
+		}
+
+	case "openapi":
+		spec, err := openapi.FromDoc(doc)
+		if err != nil {
+			return err
+		}
+		if src, err = json.MarshalIndent(spec, "", "  "); err != nil {
+			return errors.Wrap(err, "failed to encode openapi document")
+		}
+		src = append(src, '\n')
+
+	case "typescript":
+		if *dedup {
+			loldoc.Dedup(doc)
+		}
+		src = typescript.Generate(doc)
+
+	default:
+		return errors.Errorf("unknown output format %q", *outFormat)
 	}
 
-	generated := New(doc).Generate()
-	src, err := formatFile(targetFile, generated)
+	return writeOutput(out, src)
+}
+
+// loadDoc parses documents at path (See parseInputs), and suffixes older versions of resources.
+func loadDoc(c context.Context, path string, filter loldoc.Filter) (*loldoc.Doc, error) {
+	doc, err := parseInputs(c, path, filter)
 	if err != nil {
-		os.Stderr.Write(generated)
-		logging.Errorf(c, "failed to format generated go file: %+v", err)
-		return
+		return nil, errors.Wrap(err, "failed to parse lol api doc")
+	}
+	if filter == nil {
+		for _, e := range patcher.Unused() {
+			logging.Warningf(c, "unused patch: %s", e)
+		}
 	}
+	if err := loldoc.SuffixVersions(doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
 
-	if err := ioutil.WriteFile(targetFile, src, 0644); err != nil {
-		logging.Errorf(c, "failed to write %q\nError: %+v", targetFile, err)
-		return
+// goOptions configures generateGo.
+type goOptions struct {
+	pkgName  string
+	dedup    bool // merge identical classes
+	codec    bool // generate json methods of response classes
+	fixtures bool // generate the fixture test
+}
+
+// goFiles is the output of generateGo. codec and fixtures are nil unless requested.
+type goFiles struct {
+	generated, codec, fixtures []byte
+}
+
+// generateGo generates formatted go files of doc, as go generate does.
+func generateGo(c context.Context, doc *loldoc.Doc, opts goOptions) (goFiles, error) {
+	if opts.dedup {
+		for _, name := range loldoc.Dedup(doc) {
+			logging.Debugf(c, "merged identical classes into %s", name)
+		}
+	}
+
+	var files goFiles
+	for _, f := range []struct {
+		name     string
+		enabled  bool
+		dst      *[]byte
+		generate func(*Generator) []byte
+	}{
+		{"go", true, &files.generated, (*Generator).Generate},
+		{"codec", opts.codec, &files.codec, (*Generator).GenerateCodec},
+		{"fixtures", opts.fixtures, &files.fixtures, (*Generator).GenerateFixtures},
+	} {
+		if !f.enabled {
+			continue
+		}
+		generated := f.generate(New(doc, opts.pkgName))
+		src, err := formatFile("", generated)
+		if err != nil {
+			os.Stderr.Write(generated)
+			return goFiles{}, errors.Wrapf(err, "failed to format generated %s file", f.name)
+		}
+		*f.dst = src
+	}
+	return files, nil
+}
+
+// writeOutput writes src to out, or checks out is up to date with -check.
+func writeOutput(out string, src []byte) error {
+	switch {
+	case out == "" || out == "-":
+		if *check {
+			return errors.New("-check requires -out")
+		}
+		_, err := os.Stdout.Write(src)
+		return err
+
+	case *check:
+		old, err := ioutil.ReadFile(out)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(old, src) {
+			return errors.Errorf("%s is out of date (first difference at line %d). run go generate", out, firstDiffLine(old, src))
+		}
+		return nil
+
+	default:
+		if err := ioutil.WriteFile(out, src, 0644); err != nil {
+			return errors.Wrapf(err, "failed to write %q", out)
+		}
+		return nil
 	}
 }
 
+// parseInputs parses a html or OpenAPI json file, every such file in a directory or a document at url.
+// Like loldoc.ParseFiltered, doc is returned with patcher.ErrPatchesRequired.
+func parseInputs(c context.Context, path string, filter loldoc.Filter) (*loldoc.Doc, error) {
+	srcs := []string{path}
+	if fi, err := os.Stat(path); err == nil && fi.IsDir() {
+		srcs = nil
+		for _, pattern := range []string{"*.html", "*.json"} {
+			matches, err := filepath.Glob(filepath.Join(path, pattern))
+			if err != nil {
+				return nil, err
+			}
+			srcs = append(srcs, matches...)
+		}
+		if len(srcs) == 0 {
+			return nil, errors.Errorf("no html or json file in %q", path)
+		}
+	}
+
+	doc := &loldoc.Doc{}
+	seen := make(map[string]string)
+	var missing error
+	for _, src := range srcs {
+		d, err := parseInput(c, src, filter)
+		if _, ok := err.(patcher.ErrPatchesRequired); ok {
+			missing = err // keep parsing to report every missing patch.
+		} else if err != nil {
+			return nil, errors.Wrapf(err, "%s", src)
+		}
+
+		// versions of a resource are generated side by side by loldoc.SuffixVersions.
+		for _, res := range d.Resources {
+			key := res.ID + " " + res.Version
+			if prev, ok := seen[key]; ok {
+				return nil, errors.Errorf("resource %q (%s) is defined in both %q and %q", res.ID, res.Version, prev, src)
+			}
+			seen[key] = src
+			doc.Resources = append(doc.Resources, res)
+		}
+	}
+	return doc, missing
+}
+
+// parseInput parses an OpenAPI document if src is a json file, or a html document otherwise.
+func parseInput(c context.Context, src string, filter loldoc.Filter) (*loldoc.Doc, error) {
+	if strings.HasSuffix(src, ".json") {
+		spec, err := openapi.Open(src)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to open openapi document %q", src)
+		}
+		return openapi.ToDoc(c, spec, filter)
+	}
+
+	gqDoc, err := loldoc.OpenGoQueryDoc(src)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create goquery document from %q", src)
+	}
+	return loldoc.ParseFiltered(c, gqDoc, filter)
+}
+
+// resourceFilter returns nil if every resource should be generated.
+func resourceFilter(include, exclude string) loldoc.Filter {
+	if include == "" && exclude == "" {
+		return nil
+	}
+
+	set := func(s string) map[string]bool {
+		m := make(map[string]bool)
+		for _, id := range strings.Split(s, ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				m[id] = true
+			}
+		}
+		return m
+	}
+	included, excluded := set(include), set(exclude)
+
+	return func(resID string) bool {
+		if len(included) != 0 && !included[resID] {
+			return false
+		}
+		return !excluded[resID]
+	}
+}
+
+func firstDiffLine(a, b []byte) int {
+	line := 1
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			break
+		}
+		if a[i] == '\n' {
+			line++
+		}
+	}
+	return line
+}
+
 const pkgPath = "github.com/kdy1997/go-lol"
 const uriTemplatesPkg = pkgPath + "/internal/uritemplates"
-const pkgName = "lol"
 
 type Generator struct {
 	bytes.Buffer
 
-	doc *loldoc.Doc
+	doc     *loldoc.Doc
+	pkgName string
+
+	// classes is a set of names of response classes, used by GenerateCodec.
+	classes map[string]bool
+	// shared is a set of generated types shared by identical classes.
+	shared map[string]bool
 }
 
-func New(doc *loldoc.Doc) *Generator {
+func New(doc *loldoc.Doc, pkgName string) *Generator {
 	return &Generator{
-		doc: doc,
+		doc:     doc,
+		pkgName: pkgName,
+		shared:  make(map[string]bool),
 	}
 }
 
@@ -81,10 +345,13 @@ func (g *Generator) Generate() []byte {
 
 	for _, res := range g.doc.Resources {
 		g.generateResource(res)
-		for _, s := range res.Definitions {
-			g.generateResponseClass(s)
+		for _, s := range res.SortedDefinitions() {
+			g.generateResponseClass(res, s)
 		}
 	}
+	g.generateInterfaces()
+	g.generateFake()
+	g.generateOperationTable()
 
 	src := g.Bytes()
 	return src
@@ -93,8 +360,9 @@ func (g *Generator) Generate() []byte {
 func (g *Generator) generateHeader() {
 	g.P(`// Generated by go-lol-generator. DO NOT EDIT.`)
 	g.P()
-	g.P(`package `, pkgName)
+	g.P(`package `, g.pkgName)
 
+	g.P(`import "bytes"`)
 	g.P(`import "encoding/json"`)
 	g.P(`import "io"`)
 	g.P(`import "strconv"`)
@@ -106,6 +374,7 @@ func (g *Generator) generateHeader() {
 	g.P(`import `, strconv.Quote(uriTemplatesPkg))
 	g.P()
 
+	g.P(`var _ = bytes.NewReader`)
 	g.P(`var _ = json.Marshal`)
 	g.P(`var _ = io.EOF`)
 }
@@ -118,23 +387,36 @@ func (g *Generator) generateResource(res loldoc.Resource) {
 
 func (g *Generator) generateOperation(res loldoc.Resource, op *loldoc.Operation) {
 	overridedMapKey := op.OverridedMapKey
-	hasOverride := overridedMapKey != types.Invalid
-
-	ret := op.OrigReturnType
-
-	if hasOverride {
-		if m, ok := ret.(*types.Map); ok {
-			ret = types.NewMap(types.Typ[overridedMapKey], m.Elem())
-		} else {
-			panic("invalid override")
-		}
-	}
+	ret := op.ReturnType()
 
 	g.generateOpType(op)
 	g.generateOpCreatorFunc(res, op)
+	g.generateOpValidateFunc(op)
 	g.generateOpDoRequestFunc(op)
 
+	if ret == nil { // operation without response body
+		g.P(`func (c *`, callStructOf(op), `) Do() error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+
+	res, err := c.doRequest()
+	if res != nil && res.Body != nil {
+		defer closeBody(res)
+	}
+	if err != nil { return err }
+
+	return verifyResponse(res)
+}`)
+		g.P()
+		return
+	}
+
 	g.P(`func (c *`, callStructOf(op), `) Do() (`, ret, `, error) {
+	if err := c.Validate(); err != nil {
+		return `, ZeroOf(ret), `, err
+	}
+
 	res, err := c.doRequest()
 	if res != nil && res.Body != nil {
 		defer closeBody(res)
@@ -147,7 +429,11 @@ func (g *Generator) generateOperation(res loldoc.Resource, op *loldoc.Operation)
 `)
 
 	g.DeclareVar(`ret`, op.OrigReturnType)
-	g.P(`if err := json.NewDecoder(res.Body).Decode(&ret); err != nil { return `, ZeroOf(ret), `, err }`)
+	if _, ok := op.OrigReturnType.(*types.Pointer); ok {
+		g.P(`if err := decodeResponse(res, ret); err != nil { return `, ZeroOf(ret), `, err }`)
+	} else {
+		g.P(`if err := decodeResponse(res, &ret); err != nil { return `, ZeroOf(ret), `, err }`)
+	}
 	if overridedMapKey != 0 {
 		g.DeclareVar(`data`, ret)
 		g.P(`for k, v := range ret {`)
@@ -180,20 +466,7 @@ func (g *Generator) generateOpCreatorFunc(res loldoc.Resource, op *loldoc.Operat
 	g.P(`//`)
 	g.P(`//    `, op.HTTPMethod, `: `, res.APIBase(), op.RequestPath)
 
-	// required arguments
-	var args string
-	if op.IsRegional() {
-		args += `, region Region`
-	}
-	for _, p := range op.PathParams {
-		if loldoc.IsRegion(p) {
-			continue
-		}
-
-		args += `,  ` + p.Name + ` ` + p.Type.String()
-	}
-
-	g.P(`func (c `, clientType(op), `) `, op.MethodName, `(ctx context.Context`, args, `) *`, callStructOf(op), ` {`)
+	g.P(`func (c `, clientType(op), `) `, opSignature(op, `*`+callStructOf(op)), ` {`)
 	g.P(`path := make(map[string]string)`)
 
 	for _, p := range op.PathParams {
@@ -222,6 +495,24 @@ func (g *Generator) generateOpCreatorFunc(res loldoc.Resource, op *loldoc.Operat
 		g.P(`return c`)
 		g.P(`}`)
 	}
+
+	g.P(`// Options configures timeout, headers, http client, cache, retries and priority of the call.`)
+	g.P(`func (c *`, callStructOf(op), `) Options(opts ...CallOption) *`, callStructOf(op), ` {`)
+	g.P(`for _, opt := range opts {`)
+	g.P(`opt(&c.opts)`)
+	g.P(`}`)
+	g.P(`return c`)
+	g.P(`}`)
+	g.P()
+
+	if op.Body != nil {
+		g.P(`// Body configures request body, which is encoded as JSON.`)
+		g.PrintComments(op.Body.Description)
+		g.P(`func (c *`, callStructOf(op), `) Body(v `, op.Body.Type, `) (*`, callStructOf(op), `) {`)
+		g.P(`c.body = v`)
+		g.P(`return c`)
+		g.P(`}`)
+	}
 }
 
 func (g *Generator) generateOpType(op *loldoc.Operation) {
@@ -235,22 +526,307 @@ func (g *Generator) generateOpType(op *loldoc.Operation) {
 	if op.IsRegional() {
 		g.P(`	region Region`)
 	}
+	if op.Body != nil {
+		g.P(`	body `, op.Body.Type)
+	}
+	g.P(`	opts callOptions`)
+	g.P(`}`)
+	g.P()
+}
+
+// generateInterfaces prints an interface per resource, and API embedding all of them.
+// Methods of the interfaces return a call interface per operation (e.g. SummonersCaller),
+// which Client.API implements with calls of Client.
+func (g *Generator) generateInterfaces() {
+	for _, res := range g.doc.Resources {
+		g.P()
+		if res.Version != "" {
+			g.P(`// `, interfaceOf(res), ` is operations of resource "`, res.ID, `" (`, res.Version, `).`)
+		} else {
+			g.P(`// `, interfaceOf(res), ` is operations of resource "`, res.ID, `".`)
+		}
+		g.P(`type `, interfaceOf(res), ` interface {`)
+		for _, op := range res.Operations {
+			g.P(`// `, op.Description)
+			g.P(opSignature(op, callerOf(op)))
+		}
+		g.P(`}`)
+	}
+
+	g.P()
+	g.P(`// API is every operation of riot api. It's implemented by Client.API, and Fake for tests.`)
+	g.P(`type API interface {`)
+	for _, res := range g.doc.Resources {
+		g.P(interfaceOf(res))
+	}
+	g.P(`}`)
+	g.P()
+	g.P(`var _ API = clientAPI{}`)
+	g.P(`var _ API = (*Fake)(nil)`)
+	g.P()
+	g.P(`// API returns the client as API, so that code using it can be tested with Fake.`)
+	g.P(`func (c Client) API() API {`)
+	g.P(`return clientAPI{c}`)
+	g.P(`}`)
+	g.P()
+	g.P(`// clientAPI implements API with calls of Client.`)
+	g.P(`type clientAPI struct {`)
+	g.P(`Client`)
+	g.P(`}`)
+
+	for _, res := range g.doc.Resources {
+		for _, op := range res.Operations {
+			g.generateOpCaller(op)
+		}
+	}
+}
+
+// handWrittenCallMethods is methods of calls written by hand (e.g. in match_stream.go), which are added to
+// call interfaces. Fake implements them by hand as well.
+var handWrittenCallMethods = map[string][]string{
+	"Match": {`Stream(s MatchStream) (*MatchDetail, error)`},
+}
+
+// generateOpCaller prints the call interface of op, and its implementation wrapping the call of Client.
+func (g *Generator) generateOpCaller(op *loldoc.Operation) {
+	caller, wrapper := callerOf(op), funcName(callerOf(op), false)
+
+	g.P()
+	g.P(`// `, caller, ` is a call of `, op.MethodName, ` returned by API. See `, callStructOf(op), `.`)
+	g.P(`type `, caller, ` interface {`)
+	for _, q := range op.QueryParams {
+		g.P(funcName(q.Name, true), `(v `, q.Type, `) `, caller)
+	}
+	if op.Body != nil {
+		g.P(`Body(v `, op.Body.Type, `) `, caller)
+	}
+	g.P(`Options(opts ...CallOption) `, caller)
+	g.P(`Validate() error`)
+	g.P(`URL() (string, error)`)
+	for _, m := range handWrittenCallMethods[op.MethodName] {
+		g.P(m)
+	}
+	if ret := op.ReturnType(); ret != nil {
+		g.P(`Do() (`, ret, `, error)`)
+	} else {
+		g.P(`Do() error`)
+	}
 	g.P(`}`)
+
 	g.P()
+	g.P(`// `, wrapper, ` is `, callStructOf(op), ` as `, caller, `.`)
+	g.P(`type `, wrapper, ` struct {`)
+	g.P(`*`, callStructOf(op))
+	g.P(`}`)
+	g.P()
+	g.generateCallerSetters(op, wrapper, callStructOf(op))
+
+	g.P()
+	g.P(`func (a clientAPI) `, opSignature(op, caller), ` {`)
+	g.P(`return `, wrapper, `{a.Client.`, op.MethodName, `(`, strings.Join(opArgs(op), `, `), `)}`)
+	g.P(`}`)
+}
+
+// generateCallerSetters prints setters of typ, which set parameters of the embedded field and return the receiver
+// as the call interface of op.
+func (g *Generator) generateCallerSetters(op *loldoc.Operation, typ, embedded string) {
+	caller := callerOf(op)
+	for _, q := range op.QueryParams {
+		name := funcName(q.Name, true)
+		g.P(`func (c `, typ, `) `, name, `(v `, q.Type, `) `, caller, ` {`)
+		g.P(`c.`, embedded, `.`, name, `(v)`)
+		g.P(`return c`)
+		g.P(`}`)
+		g.P()
+	}
+	if op.Body != nil {
+		g.P(`func (c `, typ, `) Body(v `, op.Body.Type, `) `, caller, ` {`)
+		g.P(`c.`, embedded, `.Body(v)`)
+		g.P(`return c`)
+		g.P(`}`)
+		g.P()
+	}
+	g.P(`func (c `, typ, `) Options(opts ...CallOption) `, caller, ` {`)
+	g.P(`c.`, embedded, `.Options(opts...)`)
+	g.P(`return c`)
+	g.P(`}`)
+}
+
+// generateOperationTable prints metadata of every operation, which is returned by Operations.
+func (g *Generator) generateOperationTable() {
+	g.P()
+	g.P(`var operations = []Operation{`)
+	for _, res := range g.doc.Resources {
+		for _, op := range res.Operations {
+			g.P(`{`)
+			g.P(`Name: `, strconv.Quote(op.MethodName), `,`)
+			g.P(`Description: `, strconv.Quote(op.Description), `,`)
+			g.P(`Method: `, strconv.Quote(op.HTTPMethod), `,`)
+			g.P(`Path: `, strconv.Quote(op.RequestPath), `,`)
+			g.P(`template: `, templateOf(op), `,`)
+			if op.APIBase() != "" {
+				g.P(`BaseURL: `, strconv.Quote(op.APIBase()), `,`)
+			}
+			g.P(`Routing: `, op.Routing().String(), `,`)
+			g.P(`ResourceID: `, strconv.Quote(res.ID), `,`)
+			g.P(`ResourceVersion: `, strconv.Quote(res.Version), `,`)
+			if op.IsRegional() {
+				g.P(`Regions: []Region{`, strings.Join(op.SupportedRegions(), ", "), `},`)
+			}
+			g.P(`NeedAPIKey: `, op.NeedAPIKey(), `,`)
+			if op.RateLimitNotes != "" {
+				g.P(`RateLimitNotes: `, strconv.Quote(op.RateLimitNotes), `,`)
+			}
+			if len(op.ResponseErrors) != 0 {
+				g.P(`Errors: []OperationError{`)
+				for _, e := range op.ResponseErrors {
+					g.P(`{`, e.Code, `, `, strconv.Quote(e.Reason), `},`)
+				}
+				g.P(`},`)
+			}
+			g.P(`},`)
+		}
+	}
+	g.P(`}`)
+}
+
+// generateFake prints methods of Fake. Calls created by Fake are validated like calls of Client,
+// and return results set by SetXxx.
+func (g *Generator) generateFake() {
+	for _, res := range g.doc.Resources {
+		for _, op := range res.Operations {
+			caller := callerOf(op)
+			fakeCall := "fake" + callStructOf(op)
+			wrapper := funcName(caller, false)
+
+			g.P()
+			g.P(`// `, fakeCall, ` is a call of Fake, which returns result set by Set`, op.MethodName, `.`)
+			g.P(`type `, fakeCall, ` struct {`)
+			g.P(wrapper)
+			g.P(`fake *Fake`)
+			g.P(`}`)
+			g.P()
+			g.generateCallerSetters(op, fakeCall, wrapper)
+			g.P()
+			if ret := op.ReturnType(); ret != nil {
+				g.P(`func (c `, fakeCall, `) Do() (`, ret, `, error) {`)
+				g.P(`if err := c.Validate(); err != nil {`)
+				g.P(`return `, ZeroOf(ret), `, err`)
+				g.P(`}`)
+				g.P(`v, err := c.fake.result(`, strconv.Quote(op.MethodName), `)`)
+				g.P(`ret, _ := v.(`, ret, `)`)
+				g.P(`return ret, err`)
+			} else {
+				g.P(`func (c `, fakeCall, `) Do() error {`)
+				g.P(`if err := c.Validate(); err != nil {`)
+				g.P(`return err`)
+				g.P(`}`)
+				g.P(`_, err := c.fake.result(`, strconv.Quote(op.MethodName), `)`)
+				g.P(`return err`)
+			}
+			g.P(`}`)
+
+			g.P()
+			g.P(`// `, op.MethodName, ` returns a call which returns result set by Set`, op.MethodName, `.`)
+			g.P(`func (f *Fake) `, opSignature(op, caller), ` {`)
+			g.P(`c := `, clientType(op), `{}.`, op.MethodName, `(`, strings.Join(opArgs(op), `, `), `)`)
+			g.P(`return `, fakeCall, `{`, wrapper, `{c}, f}`)
+			g.P(`}`)
+
+			g.P()
+			if ret := op.ReturnType(); ret != nil {
+				g.P(`// Set`, op.MethodName, ` sets result of `, op.MethodName, `.`)
+				g.P(`func (f *Fake) Set`, op.MethodName, `(v `, ret, `, err error) *Fake {`)
+				g.P(`f.set(`, strconv.Quote(op.MethodName), `, v, err)`)
+			} else {
+				g.P(`// Set`, op.MethodName, ` sets error returned by `, op.MethodName, `.`)
+				g.P(`func (f *Fake) Set`, op.MethodName, `(err error) *Fake {`)
+				g.P(`f.set(`, strconv.Quote(op.MethodName), `, nil, err)`)
+			}
+			g.P(`return f`)
+			g.P(`}`)
+		}
+	}
+}
+
+// generateOpValidateFunc prints Validate, which checks required parameters,
+// length of lists, allowed values and parameters required together.
+func (g *Generator) generateOpValidateFunc(op *loldoc.Operation) {
+	valueOf := func(name string) string {
+		if op.PathParams.Has(name) {
+			return `c.pathParams[` + strconv.Quote(name) + `]`
+		}
+		return `c.query.Get(` + strconv.Quote(name) + `)`
+	}
+
+	var checks []string
+	for _, p := range append(append(loldoc.Parameters(nil), op.PathParams...), op.QueryParams...) {
+		if loldoc.IsRegion(p) {
+			continue
+		}
+
+		name, value := strconv.Quote(p.Name), valueOf(p.Name)
+		if p.Required {
+			checks = append(checks, fmt.Sprintf(`v.required(%s, %s, %t)`, name, value, isNumber(p.Type)))
+		}
+		if p.MaxItems != 0 {
+			checks = append(checks, fmt.Sprintf(`v.maxItems(%s, %s, %d)`, name, value, p.MaxItems))
+		}
+		if len(p.Enum) != 0 {
+			allowed := make([]string, 0, len(p.Enum))
+			for _, val := range p.Enum {
+				allowed = append(allowed, strconv.Quote(val))
+			}
+			checks = append(checks, fmt.Sprintf(`v.enum(%s, %s, %t, %s)`, name, value, p.Multiple, strings.Join(allowed, ", ")))
+		}
+	}
+	for _, group := range op.RequiredTogether {
+		var names, values []string
+		for _, name := range group {
+			names = append(names, strconv.Quote(name))
+			values = append(values, valueOf(name))
+		}
+		checks = append(checks, fmt.Sprintf(`v.together([]string{%s}, %s)`, strings.Join(names, ", "), strings.Join(values, ", ")))
+	}
+	if op.Body != nil && op.Body.Required {
+		checks = append(checks, `if c.body == nil { v.errorf("body", "required") }`)
+	}
+
+	g.P()
+	g.P(`// Validate checks parameters against constraints documented by riot, without sending a request.`)
+	g.P(`// It's called by Do, and returns *ValidationError listing every problem.`)
+	g.P(`func (c *`, callStructOf(op), `) Validate() error {`)
+	if len(checks) == 0 {
+		g.P(`return nil`)
+	} else {
+		g.P(`v := newValidator(`, strconv.Quote(op.MethodName), `)`)
+		for _, check := range checks {
+			g.P(check)
+		}
+		g.P(`return v.result()`)
+	}
+	g.P(`}`)
+}
+
+func isNumber(t types.Type) bool {
+	b, ok := t.(*types.Basic)
+	return ok && b.Info()&types.IsNumeric != 0
 }
 
 func (g *Generator) generateOpDoRequestFunc(op *loldoc.Operation) {
 	g.P()
-	g.P(`func (c *`, callStructOf(op), `) doRequest() (*http.Response, error) {`)
-	g.P(`var body io.Reader`)
+	g.P(`var `, templateOf(op), ` = uritemplates.MustParse(`, strconv.Quote(op.RequestPath), `)`)
+	g.P()
+	g.P(`// url returns the url of the request, with api key.`)
+	g.P(`func (c *`, callStructOf(op), `) url() (string, error) {`)
 
 	// Parameter validation
 	if op.IsRegional() {
 		g.P(`switch c.region {`)
 		g.P(`case `, strings.Join(op.SupportedRegions(), ","), `:`)
-		g.P(`default:
-			return nil, ErrNotSupportedRegion
-		}`)
+		g.P(`default:`)
+		g.P(`return "", &UnsupportedRegionError{Operation: `, strconv.Quote(op.MethodName), `, Region: c.region}`)
+		g.P(`}`)
 	}
 
 	if op.NeedAPIKey() {
@@ -266,30 +842,105 @@ func (g *Generator) generateOpDoRequestFunc(op *loldoc.Operation) {
 
 	var urlsTpl string
 
-	if op.APIBase() == "" {
-		urlsTpl = `c.region.baseURL()`
-	} else {
+	switch {
+	case op.Routing() == loldoc.FixedRouting:
 		urlsTpl = strconv.Quote(op.APIBase())
+	case op.IsRegional():
+		urlsTpl = `c.client.baseURL(c.region, ` + op.Routing().String() + `, ` + strconv.Quote(op.APIBase()) + `)`
+	default:
+		urlsTpl = `c.client.baseURL(Global, ` + op.Routing().String() + `, ` + strconv.Quote(op.APIBase()) + `)`
 	}
-	g.P(`path, err := uritemplates.Expand(`, strconv.Quote(op.RequestPath), `, c.pathParams)`)
-	g.P(`if err != nil { return nil, err }`)
+	g.P(`path, err := `, templateOf(op), `.Expand(c.pathParams)`)
+	g.P(`if err != nil { return "", err }`)
+	g.P(`return `, urlsTpl, ` + path + "?" + c.query.Encode(), nil`)
+	g.P(`}`)
+	g.P()
 
-	g.P(`urls := `, urlsTpl, ` + path + "?" + c.query.Encode()`)
+	g.P(`// URL returns the url which Do requests, with api key redacted. It does not send anything.`)
+	g.P(`func (c *`, callStructOf(op), `) URL() (string, error) {`)
+	g.P(`urls, err := c.url()`)
+	g.P(`if err != nil { return "", err }`)
+	g.P(`return redactKey(urls), nil`)
+	g.P(`}`)
 	g.P()
 
-	g.P(`return c.client.doRequest(c.ctx, `, strconv.Quote(op.HTTPMethod), `, urls, body)`)
+	g.P(`func (c *`, callStructOf(op), `) doRequest() (*http.Response, error) {`)
+	g.P(`var body io.Reader`)
+	g.P(`urls, err := c.url()`)
+	g.P(`if err != nil { return nil, err }`)
+	g.P()
+
+	if op.Body != nil {
+		g.P(`if c.body != nil {`)
+		g.P(`data, err := json.Marshal(c.body)`)
+		g.P(`if err != nil { return nil, err }`)
+		g.P(`body = bytes.NewReader(data)`)
+		g.P(`}`)
+		g.P()
+	}
+
+	g.P(`return c.client.doRequest(c.ctx, `, strconv.Quote(op.HTTPMethod), `, urls, body, &c.opts)`)
 	g.P(`}`)
 	g.P()
 }
 
-func (g *Generator) generateResponseClass(s loldoc.Schema) {
+func (g *Generator) generateResponseClass(res loldoc.Resource, s loldoc.Schema) {
+	if s.SharedName != "" {
+		g.generateSharedClass(res, s)
+		return
+	}
+
 	g.P()
 	if s.Description != "" {
 		g.P(`// `, s.Description)
 		g.P(`//`)
 	}
 	g.P(`// resource: "`, s.ResID(), `", original name: "`, s.OrigName, `"`)
-	g.P(`type `, s.StructName, ` struct {`)
+	g.generateStruct(s.StructName, s)
+}
+
+// generateSharedClass generates a type shared by classes merged by loldoc.Dedup,
+// when it's called for the first of them, and an alias for an old name of the class.
+func (g *Generator) generateSharedClass(res loldoc.Resource, s loldoc.Schema) {
+	if !g.shared[s.SharedName] {
+		g.shared[s.SharedName] = true
+
+		g.P()
+		if s.Description != "" {
+			g.P(`// `, s.Description)
+			g.P(`//`)
+		}
+		g.P(`// shared by identical classes:`)
+		for _, r := range g.doc.Resources {
+			for _, other := range r.SortedDefinitions() {
+				if other.SharedName != s.SharedName {
+					continue
+				}
+				if r.Suffix != "" {
+					g.P(`//  - resource: "`, other.ResID(), `" (`, r.Version, `), original name: "`, other.OrigName, `"`)
+				} else {
+					g.P(`//  - resource: "`, other.ResID(), `", original name: "`, other.OrigName, `"`)
+				}
+			}
+		}
+		g.generateStruct(s.SharedName, s)
+	}
+
+	if s.StructName != s.SharedName {
+		g.P()
+		g.P(`// `, s.StructName, ` is an alias kept for compatibility.`)
+		g.P(`//`)
+		if res.Suffix != "" {
+			g.P(`// Deprecated: use `, s.SharedName, `, which is shared by identical classes of newer versions.`)
+		} else {
+			g.P(`// Deprecated: use `, s.SharedName, `, which is shared by identical classes of other resources.`)
+		}
+		g.P(`type `, s.StructName, ` = `, s.SharedName)
+	}
+}
+
+func (g *Generator) generateStruct(name string, s loldoc.Schema) {
+	g.P(`type `, name, ` struct {`)
 	for _, f := range s.Fields {
 		g.PrintComments(f.Description)
 		tag := "`" + `json:"` + f.OrigName() + `"` + "`"
@@ -416,6 +1067,62 @@ func clientType(op *loldoc.Operation) string {
 	return "StaticClient"
 }
 
+// opSignature returns signature of the method creating a call of op, without receiver.
+// ret is the type of the call (e.g. "*SummonersCall" or "SummonersCaller").
+func opSignature(op *loldoc.Operation, ret string) string {
+	// required arguments
+	var args string
+	if op.IsRegional() {
+		args += `, region Region`
+	}
+	for _, p := range op.PathParams {
+		if loldoc.IsRegion(p) {
+			continue
+		}
+
+		args += `, ` + p.Name + ` ` + p.Type.String()
+	}
+	return op.MethodName + `(ctx context.Context` + args + `) ` + ret
+}
+
+// opArgs returns names of arguments of the method creating a call of op.
+func opArgs(op *loldoc.Operation) []string {
+	args := []string{`ctx`}
+	if op.IsRegional() {
+		args = append(args, `region`)
+	}
+	for _, p := range op.PathParams {
+		if !loldoc.IsRegion(p) {
+			args = append(args, p.Name)
+		}
+	}
+	return args
+}
+
+// interfaceOf returns name of the interface of a resource (e.g. "LolStaticDataAPI" for "lol-static-data",
+// and "SummonerV1_3API" for an older version of "summoner"). The name can be patched (e.g. "ChampionMasteryAPI").
+func interfaceOf(res loldoc.Resource) string {
+	name := patcher.ResourceName(res.ID)
+	if name == "" {
+		for _, part := range strings.Split(res.ID, "-") {
+			if part != "" {
+				name += typeName(part, true)
+			}
+		}
+	}
+	return name + res.Suffix + "API"
+}
+
 func callStructOf(op *loldoc.Operation) string {
 	return op.MethodName + "Call"
 }
+
+// callerOf returns name of the call interface of op, which is returned by API.
+func callerOf(op *loldoc.Operation) string {
+	return op.MethodName + "Caller"
+}
+
+// templateOf returns name of the package-level variable holding the parsed request path of op.
+func templateOf(op *loldoc.Operation) string {
+	return funcName(op.MethodName, false) + "Template"
+}
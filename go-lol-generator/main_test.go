@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/kdy1997/go-lol/go-lol-generator/patcher"
+	"github.com/luci/luci-go/common/logging/memlogger"
+	. "github.com/smartystreets/goconvey/convey"
+	"golang.org/x/net/context"
+)
+
+// Golden files are rewritten by
+//
+//	go test github.com/kdy1997/go-lol/go-lol-generator -update
+//
+// which regenerates lol.generated.go, lol.codec.generated.go and fixtures.generated_test.go as well.
+var update = flag.Bool("update", false, "update golden files with the current output")
+
+// shouldMatchGolden compares actual with the golden file at expected[0], or rewrites it with -update.
+func shouldMatchGolden(actual interface{}, expected ...interface{}) string {
+	src, path := actual.([]byte), expected[0].(string)
+	if *update {
+		if err := ioutil.WriteFile(path, src, 0644); err != nil {
+			return err.Error()
+		}
+		return ""
+	}
+
+	golden, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err.Error() + " (run go test with -update to create it)"
+	}
+	if !bytes.Equal(golden, src) {
+		return path + " differs from generated output at line " + strconv.Itoa(firstDiffLine(golden, src)) +
+			" (run go test with -update if the change is intended)"
+	}
+	return ""
+}
+
+// goldenFiles runs the go output of the generator on src with default flags, like go generate does.
+func goldenFiles(src string, all bool) (goFiles, error) {
+	c := memlogger.Use(context.Background())
+	doc, err := loadDoc(c, src, nil)
+	if err != nil {
+		return goFiles{}, err
+	}
+	return generateGo(c, doc, goOptions{pkgName: "lol", dedup: true, codec: all, fixtures: all})
+}
+
+func TestGolden(t *testing.T) {
+	if err := patcher.LoadFile("patcher/patches.json"); err != nil {
+		t.Fatal(err)
+	}
+
+	Convey("Generated go files match golden files", t, func() {
+		Convey("methods.html generates files of package lol", func() {
+			files, err := goldenFiles("loldoc/methods.html", true)
+			So(err, ShouldBeNil)
+			So(files.generated, shouldMatchGolden, "../lol.generated.go")
+			So(files.codec, shouldMatchGolden, "../lol.codec.generated.go")
+			So(files.fixtures, shouldMatchGolden, "../fixtures.generated_test.go")
+		})
+
+		fixtures, err := filepath.Glob("testdata/*.html")
+		So(err, ShouldBeNil)
+		jsonFixtures, err := filepath.Glob("testdata/*.json")
+		So(err, ShouldBeNil)
+		fixtures = append(fixtures, jsonFixtures...)
+		So(fixtures, ShouldNotBeEmpty)
+
+		for _, src := range fixtures {
+			src := src
+			Convey(src, func() {
+				files, err := goldenFiles(src, false)
+				So(err, ShouldBeNil)
+				So(files.generated, shouldMatchGolden, src+".golden")
+			})
+		}
+	})
+}
@@ -0,0 +1,319 @@
+package openapi
+
+import (
+	"encoding/json"
+	"go/types"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kdy1997/go-lol/go-lol-generator/loldoc"
+	"github.com/pkg/errors"
+)
+
+const (
+	jsonMediaType   = "application/json"
+	apiKeyScheme    = "api_key"
+	schemaRefPrefix = "#/components/schemas/"
+)
+
+// integerKeyPattern matches keys of json objects used as map[int64]T.
+const integerKeyPattern = `^-?[0-9]+$`
+
+// handwritten is schemas of types which are declared by hand in package lol.
+var handwritten = map[string]func() *Schema{
+	// See SpellRange.UnmarshalJSON.
+	"SpellRange": func() *Schema {
+		ranges := &Schema{Type: "array", Items: &Schema{Type: "integer", Format: "int32"}}
+		return &Schema{
+			Description: `Range of a spell. It's "self", an array of ranges or an object with "Ranges" property.`,
+			OneOf: []*Schema{
+				{Type: "string", Enum: []string{"self"}},
+				ranges,
+				{Type: "object", Properties: Properties{{Name: "Ranges", Schema: ranges}}},
+			},
+		}
+	},
+}
+
+// ErrUnknownTypes is returned if a document refers to classes which are not declared.
+type ErrUnknownTypes []string
+
+func (e ErrUnknownTypes) Error() string {
+	return "openapi: unknown types: " + strings.Join(e, ", ")
+}
+
+// FromDoc converts doc to an OpenAPI document.
+//
+// Overrided map keys are described by propertyNames, and
+// types declared by hand in package lol (e.g. SpellRange) are described as well.
+func FromDoc(doc *loldoc.Doc) (*Document, error) {
+	e := &exporter{
+		Document: &Document{
+			OpenAPI: Version,
+			Info: Info{
+				Title:   "League of Legends API",
+				Version: "1.0.0",
+			},
+			Paths: make(map[string]*PathItem),
+			Components: Components{
+				Schemas: make(map[string]*Schema),
+				SecuritySchemes: map[string]*SecurityScheme{
+					apiKeyScheme: {Type: "apiKey", Name: "api_key", In: "query"},
+				},
+			},
+		},
+		refs: make(map[string]bool),
+	}
+
+	for _, res := range doc.Resources {
+		e.Tags = append(e.Tags, &Tag{Name: tagOf(res), Version: res.Version, Regions: res.Regions})
+		for _, op := range res.Operations {
+			e.addOperation(res, op)
+		}
+		for _, s := range res.SortedDefinitions() {
+			e.Components.Schemas[s.StructName] = e.classSchema(res, s)
+		}
+	}
+
+	var unknown []string
+	for name := range e.refs {
+		if _, ok := e.Components.Schemas[name]; ok {
+			continue
+		}
+		if f, ok := handwritten[name]; ok {
+			e.Components.Schemas[name] = f()
+			continue
+		}
+		unknown = append(unknown, name)
+	}
+	if len(unknown) != 0 {
+		sort.Strings(unknown)
+		return nil, ErrUnknownTypes(unknown)
+	}
+
+	return e.Document, nil
+}
+
+// tagOf returns the tag of operations of res. Older versions generated side by side are
+// tagged like "summoner-v1.3", so that they are not merged with the newest one.
+func tagOf(res loldoc.Resource) string {
+	if res.Suffix == "" {
+		return res.ID
+	}
+	return res.ID + "-" + res.Version
+}
+
+type exporter struct {
+	*Document
+	refs map[string]bool // referenced schemas
+}
+
+func (e *exporter) addOperation(res loldoc.Resource, op *loldoc.Operation) {
+	item, ok := e.Paths[op.RequestPath]
+	if !ok {
+		item = &PathItem{Servers: []*Server{serverOf(res)}}
+		e.Paths[op.RequestPath] = item
+	}
+
+	o := &Operation{
+		OperationID:      op.MethodName,
+		Tags:             []string{tagOf(res)},
+		Summary:          op.Description,
+		Description:      op.ImplNotes,
+		RateLimitNotes:   op.RateLimitNotes,
+		RequiredTogether: op.RequiredTogether,
+		Responses:        make(map[string]*Response),
+		Security:         []map[string][]string{},
+	}
+	if res.NeedAPIKey() {
+		o.Security = append(o.Security, map[string][]string{apiKeyScheme: {}})
+	}
+
+	for _, p := range op.PathParams {
+		param := &Parameter{
+			Name:        p.Name,
+			In:          "path",
+			Description: p.Description,
+			Required:    true,
+			Schema:      e.schemaOf(p.Type),
+		}
+		if p.Name == "region" {
+			param.Schema = &Schema{Type: "string", Enum: lowerAll(res.Regions)}
+		}
+		setConstraints(param, p)
+		o.Parameters = append(o.Parameters, param)
+	}
+	for _, p := range op.QueryParams {
+		param := &Parameter{
+			Name:        p.Name,
+			In:          "query",
+			Description: p.Description,
+			Required:    p.Required,
+			Schema:      e.schemaOf(p.Type),
+		}
+		if _, ok := p.Type.(*types.Slice); ok { // joined with ','
+			explode := false
+			param.Style, param.Explode = "form", &explode
+		}
+		setConstraints(param, p)
+		o.Parameters = append(o.Parameters, param)
+	}
+
+	if op.Body != nil {
+		o.RequestBody = &RequestBody{
+			Description: op.Body.Description,
+			Required:    op.Body.Required,
+			Content: map[string]*MediaType{
+				jsonMediaType: {Schema: e.schemaOf(op.Body.Type)},
+			},
+		}
+	}
+
+	ok200 := &Response{Description: http.StatusText(http.StatusOK)}
+	if op.OrigReturnType != nil {
+		s := e.schemaOf(op.OrigReturnType)
+		if op.OverridedMapKey != types.Invalid {
+			s.PropertyNames = keySchema(types.Typ[op.OverridedMapKey])
+		}
+		ok200.Content = map[string]*MediaType{jsonMediaType: {Schema: s}}
+	}
+	o.Responses[strconv.Itoa(http.StatusOK)] = ok200
+	for _, re := range op.ResponseErrors {
+		desc := re.Reason
+		if desc == "" {
+			desc = http.StatusText(re.Code)
+		}
+		o.Responses[strconv.Itoa(re.Code)] = &Response{Description: desc}
+	}
+
+	switch op.HTTPMethod {
+	case "GET":
+		item.Get = o
+	case "POST":
+		item.Post = o
+	case "PUT":
+		item.Put = o
+	case "DELETE":
+		item.Delete = o
+	default:
+		panic(errors.Errorf("openapi: unknown http method %q", op.HTTPMethod))
+	}
+}
+
+func setConstraints(param *Parameter, p loldoc.Parameter) {
+	if len(p.Enum) != 0 {
+		param.Schema.Enum = p.Enum
+	}
+	param.Multiple = p.Multiple
+	param.MaxItems = p.MaxItems
+	if param.Schema.Type == "array" {
+		param.Schema.MaxItems = p.MaxItems
+	}
+}
+
+func (e *exporter) classSchema(res loldoc.Resource, s loldoc.Schema) *Schema {
+	cls := &Schema{
+		Type:         "object",
+		Description:  s.Description,
+		RiotName:     s.OrigName,
+		RiotResource: tagOf(res),
+		Properties:   make(Properties, 0, len(s.Fields)),
+	}
+	if s.Example != "" {
+		cls.Example = json.RawMessage(s.Example)
+	}
+	for _, f := range s.Fields {
+		fs := e.schemaOf(f.Type)
+		fs.Description = f.Description // OpenAPI 3.1 allows description next to $ref.
+		if f.Example != "" {
+			fs.Example = json.RawMessage(f.Example)
+		}
+		cls.Properties = append(cls.Properties, Property{Name: f.OrigName(), Schema: fs})
+	}
+	return cls
+}
+
+func (e *exporter) schemaOf(t types.Type) *Schema {
+	switch t := t.(type) {
+	case *types.Basic:
+		return basicSchema(t)
+	case *types.Slice:
+		return &Schema{Type: "array", Items: e.schemaOf(t.Elem())}
+	case *types.Map:
+		return &Schema{
+			Type:                 "object",
+			AdditionalProperties: e.schemaOf(t.Elem()),
+			PropertyNames:        keySchema(t.Key()),
+		}
+	case *types.Pointer:
+		return e.schemaOf(t.Elem())
+	case *types.Named:
+		name := t.Obj().Name()
+		if name == "Region" { // declared by hand, and encoded as its name.
+			return &Schema{Type: "string"}
+		}
+		e.refs[name] = true
+		return &Schema{Ref: schemaRefPrefix + name}
+	}
+	panic(errors.Errorf("openapi: unsupported type %v", t))
+}
+
+func basicSchema(t *types.Basic) *Schema {
+	switch t.Kind() {
+	case types.Bool:
+		return &Schema{Type: "boolean"}
+	case types.Int32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case types.Int64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case types.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case types.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case types.String:
+		return &Schema{Type: "string"}
+	}
+	panic(errors.Errorf("openapi: unsupported basic type %v", t))
+}
+
+// keySchema returns nil for string keys, which need no restriction.
+func keySchema(key types.Type) *Schema {
+	if b, ok := key.(*types.Basic); ok && b.Info()&types.IsInteger != 0 {
+		s := basicSchema(b)
+		return &Schema{Type: "string", Format: s.Format, Pattern: integerKeyPattern}
+	}
+	return nil
+}
+
+func serverOf(res loldoc.Resource) *Server {
+	if base := res.APIBase(); base != "" {
+		return &Server{URL: base}
+	}
+
+	regions := lowerAll(res.Regions)
+	def := ""
+	if len(regions) != 0 {
+		def = regions[0]
+	}
+	for _, r := range regions {
+		if r == "na" {
+			def = r
+		}
+	}
+	return &Server{
+		URL: "https://{region}.api.pvp.net",
+		Variables: map[string]*ServerVariable{
+			"region": {Enum: regions, Default: def},
+		},
+	}
+}
+
+func lowerAll(ss []string) []string {
+	ret := make([]string, 0, len(ss))
+	for _, s := range ss {
+		ret = append(ret, strings.ToLower(s))
+	}
+	return ret
+}
@@ -0,0 +1,165 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"go/types"
+	"strings"
+	"testing"
+
+	"github.com/kdy1997/go-lol/go-lol-generator/loldoc"
+	"github.com/kdy1997/go-lol/go-lol-generator/patcher"
+	"github.com/luci/luci-go/common/logging/memlogger"
+	. "github.com/smartystreets/goconvey/convey"
+	"golang.org/x/net/context"
+)
+
+func TestFromDoc(t *testing.T) {
+	if err := patcher.LoadFile("../patcher/patches.json"); err != nil {
+		t.Fatal(err)
+	}
+	gqDoc, err := loldoc.OpenGoQueryDoc("../loldoc/methods.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := loldoc.Parse(memlogger.Use(context.Background()), gqDoc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	Convey("FromDoc", t, func() {
+		spec, err := FromDoc(doc)
+		So(err, ShouldBeNil)
+		So(spec.OpenAPI, ShouldEqual, Version)
+
+		Convey("Describes overrided map keys", func() {
+			op := spec.Paths["/api/lol/{region}/v1.4/summoner/{summonerIds}"].Get
+			So(op.OperationID, ShouldEqual, "Summoners")
+
+			s := op.Responses["200"].Content["application/json"].Schema
+			So(s.Type, ShouldEqual, "object")
+			So(s.AdditionalProperties.Ref, ShouldEqual, "#/components/schemas/Summoner")
+			So(s.PropertyNames, ShouldResemble, &Schema{Type: "string", Format: "int64", Pattern: integerKeyPattern})
+		})
+
+		Convey("Describes SpellRange", func() {
+			So(spec.Components.Schemas["ChampionSpell"].Properties.Get("range").Ref, ShouldEqual, "#/components/schemas/SpellRange")
+			So(spec.Components.Schemas["SpellRange"].OneOf, ShouldHaveLength, 3)
+		})
+
+		Convey("Uses global host for static data", func() {
+			item := spec.Paths["/api/lol/static-data/{region}/v1.2/champion"]
+			So(item.Servers[0].URL, ShouldEqual, "https://global.api.pvp.net")
+		})
+
+		Convey("Keeps order of properties", func() {
+			data, err := json.Marshal(spec)
+			So(err, ShouldBeNil)
+
+			var decoded Document
+			So(json.Unmarshal(data, &decoded), ShouldBeNil)
+			So(decoded.Components.Schemas["Summoner"].Properties, ShouldResemble, spec.Components.Schemas["Summoner"].Properties)
+		})
+	})
+
+	Convey("ToDoc restores documents created by FromDoc", t, func() {
+		spec, err := FromDoc(doc)
+		So(err, ShouldBeNil)
+		data, err := json.Marshal(spec)
+		So(err, ShouldBeNil)
+
+		decoded, err := Decode(bytes.NewReader(data))
+		So(err, ShouldBeNil)
+		restored, err := ToDoc(memlogger.Use(context.Background()), decoded, nil)
+		So(err, ShouldBeNil)
+		So(loldoc.Diff(doc, restored), ShouldBeEmpty)
+		So(examplesOf(doc), ShouldNotBeEmpty)
+		So(examplesOf(restored), ShouldResemble, examplesOf(doc))
+
+		for _, res := range restored.Resources {
+			for _, op := range res.Operations {
+				if op.MethodName == "Summoners" {
+					So(op.OverridedMapKey, ShouldEqual, types.Int64)
+				}
+			}
+		}
+	})
+
+	Convey("ToDoc accepts riot style names", t, func() {
+		spec, err := Decode(strings.NewReader(`{
+			"openapi": "3.0.0",
+			"servers": [{"url": "https://{region}.api.pvp.net", "variables": {"region": {"enum": ["na", "kr"], "default": "na"}}}],
+			"paths": {
+				"/api/lol/{region}/v1.4/summoner/{summonerIds}": {
+					"get": {
+						"operationId": "getSummoners",
+						"tags": ["summoner-v1.4"],
+						"parameters": [
+							{"name": "region", "in": "path", "required": true, "schema": {"type": "string"}},
+							{"name": "summonerIds", "in": "path", "required": true, "schema": {"type": "string"}},
+							{"name": "X-Riot-Token", "in": "header", "schema": {"type": "string"}}
+						],
+						"responses": {
+							"200": {"description": "OK", "content": {"application/json": {"schema": {
+								"type": "object",
+								"additionalProperties": {"$ref": "#/components/schemas/summoner-v1.4.SummonerDto"}
+							}}}},
+							"404": {"description": "Not Found"}
+						}
+					}
+				}
+			},
+			"components": {"schemas": {
+				"summoner-v1.4.SummonerDto": {"type": "object", "properties": {"id": {"type": "integer", "format": "int64"}}}
+			}}
+		}`))
+		So(err, ShouldBeNil)
+
+		restored, err := ToDoc(memlogger.Use(context.Background()), spec, nil)
+		So(err, ShouldBeNil)
+		So(restored.Resources, ShouldHaveLength, 1)
+
+		res := restored.Resources[0]
+		So(res.ID, ShouldEqual, "summoner")
+		So(res.Version, ShouldEqual, "v1.4")
+		So(res.Regions, ShouldResemble, []string{"NA", "KR"})
+		So(res.Definitions, ShouldContainKey, "SummonerDto")
+		So(res.Definitions["SummonerDto"].StructName, ShouldEqual, "Summoner")
+
+		op := res.Operations[0]
+		So(op.MethodName, ShouldEqual, "Summoners")
+		So(op.PathParams[1].Type.String(), ShouldEqual, "[]int64") // patched
+		So(op.ResponseErrors, ShouldResemble, []*loldoc.ResponseError{{Code: 404, Reason: "Not Found"}})
+	})
+
+	Convey("FromDoc reports unknown types", t, func() {
+		_, err := FromDoc(&loldoc.Doc{Resources: []loldoc.Resource{{
+			ID: "x",
+			Operations: []*loldoc.Operation{{
+				MethodName:     "X",
+				HTTPMethod:     "GET",
+				RequestPath:    "/x",
+				OrigReturnType: patcher.ClassType("x", "Unknown"),
+			}},
+		}}})
+		So(err, ShouldResemble, ErrUnknownTypes{"Unknown"})
+	})
+}
+
+// examplesOf returns examples of classes and fields of doc, keyed by their names.
+func examplesOf(doc *loldoc.Doc) map[string]string {
+	examples := make(map[string]string)
+	for _, res := range doc.Resources {
+		for _, s := range res.Definitions {
+			if s.Example != "" {
+				examples[res.ID+" "+s.OrigName] = s.Example
+			}
+			for _, f := range s.Fields {
+				if f.Example != "" {
+					examples[res.ID+" "+s.OrigName+"."+f.OrigName()] = f.Example
+				}
+			}
+		}
+	}
+	return examples
+}
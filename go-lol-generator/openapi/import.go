@@ -0,0 +1,481 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"go/types"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kdy1997/go-lol/go-lol-generator/loldoc"
+	"github.com/kdy1997/go-lol/go-lol-generator/patcher"
+	"github.com/luci/luci-go/common/logging"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+// Open reads an OpenAPI document encoded in json from url (http:// or https://) or a file.
+func Open(src string) (*Document, error) {
+	var r io.ReadCloser
+	if strings.HasPrefix(src, "http://") || strings.HasPrefix(src, "https://") {
+		res, err := http.Get(src)
+		if err != nil {
+			return nil, err
+		}
+		if res.StatusCode != http.StatusOK {
+			res.Body.Close()
+			return nil, errors.Errorf("openapi: GET %s: %s", src, res.Status)
+		}
+		r = res.Body
+	} else {
+		f, err := os.Open(src)
+		if err != nil {
+			return nil, err
+		}
+		r = f
+	}
+	defer r.Close()
+
+	return Decode(r)
+}
+
+// Decode reads an OpenAPI document encoded in json.
+// Properties not used by this package are ignored.
+func Decode(r io.Reader) (*Document, error) {
+	spec := &Document{}
+	if err := json.NewDecoder(r).Decode(spec); err != nil {
+		return nil, errors.Wrap(err, "openapi: failed to decode document")
+	}
+	if !strings.HasPrefix(spec.OpenAPI, "3.") {
+		return nil, errors.Errorf("openapi: unsupported version %q", spec.OpenAPI)
+	}
+	return spec, nil
+}
+
+// ToDoc builds a loldoc.Doc from spec, so it can be emitted like a parsed html document.
+// It's the counterpart of FromDoc, and accepts documents not created by FromDoc as well.
+//
+// Every operation must have a tag, which is the resource id (e.g. "summoner").
+// Tags formatted as "summoner-v1.4" are accepted as well, if x-riot-version is not set or agrees with it.
+// Classes are object schemas in components, and are declared in the resource
+// named by x-riot-resource, or the first resource referring them.
+//
+// Names and types are patched with the current manifest of patcher as riot.go does,
+// and missing patches are reported in the same way as loldoc.ParseFiltered.
+func ToDoc(c context.Context, spec *Document, filter loldoc.Filter) (*loldoc.Doc, error) {
+	i := &importer{
+		c:      c,
+		spec:   spec,
+		filter: filter,
+		byTag:  make(map[string]*loldoc.Resource),
+		byID:   make(map[string]*loldoc.Resource),
+		owners: make(map[string]*loldoc.Resource),
+	}
+
+	for _, tag := range spec.Tags {
+		i.resource(tag.Name)
+	}
+
+	paths := make([]string, 0, len(spec.Paths))
+	for path := range spec.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		item := spec.Paths[path]
+		for _, m := range []struct {
+			method string
+			op     *Operation
+		}{
+			{"GET", item.Get},
+			{"POST", item.Post},
+			{"PUT", item.Put},
+			{"DELETE", item.Delete},
+		} {
+			if m.op == nil {
+				continue
+			}
+			if err := i.addOperation(item, m.method, path, m.op); err != nil {
+				return nil, errors.Wrapf(err, "openapi: %s %s", m.method, path)
+			}
+		}
+	}
+
+	// classes referred by classes are found while adding classes.
+	for len(i.pending) != 0 {
+		name := i.pending[0]
+		i.pending = i.pending[1:]
+		if err := i.addClass(name); err != nil {
+			return nil, errors.Wrapf(err, "openapi: class %q", name)
+		}
+	}
+
+	doc := &loldoc.Doc{}
+	for _, res := range i.resources {
+		if len(res.Regions) == 0 {
+			res.Regions = i.regions[res]
+		}
+		doc.Resources = append(doc.Resources, *res)
+		logging.Infof(c, "parsed resource %q", res.ID)
+	}
+
+	if missing := patcher.Missing(); len(missing) != 0 {
+		return doc, missing
+	}
+	return doc, nil
+}
+
+type importer struct {
+	c      context.Context
+	spec   *Document
+	filter loldoc.Filter
+
+	resources []*loldoc.Resource
+	byTag     map[string]*loldoc.Resource
+	byID      map[string]*loldoc.Resource
+	// regions found from servers and region parameters,
+	// used if tag does not have x-riot-regions.
+	regions map[*loldoc.Resource][]string
+
+	owners  map[string]*loldoc.Resource // component name -> resource declaring it
+	pending []string                    // classes to add
+}
+
+// resource returns nil if the resource is filtered out.
+func (i *importer) resource(tagName string) *loldoc.Resource {
+	if res, ok := i.byTag[tagName]; ok {
+		return res
+	}
+
+	res := &loldoc.Resource{Definitions: make(map[string]loldoc.Schema)}
+	res.ID = tagName
+	for _, tag := range i.spec.Tags {
+		if tag.Name == tagName {
+			res.Version, res.Regions = tag.Version, tag.Regions
+		}
+	}
+	if idx := strings.LastIndex(tagName, "-v"); (res.Version == "" || res.Version == tagName[idx+1:]) && idx != -1 &&
+		idx+2 < len(tagName) && '0' <= tagName[idx+2] && tagName[idx+2] <= '9' {
+		res.ID, res.Version = tagName[:idx], tagName[idx+1:]
+	}
+
+	if i.filter != nil && !i.filter(res.ID) {
+		logging.Infof(i.c, "skipping resource %q", res.ID)
+		res = nil
+	} else {
+		i.resources = append(i.resources, res)
+		i.byID[res.ID] = res
+	}
+	i.byTag[tagName] = res
+	return res
+}
+
+func (i *importer) addOperation(item *PathItem, method, path string, o *Operation) error {
+	if len(o.Tags) == 0 {
+		return errors.New("operation does not have a tag")
+	}
+	res := i.resource(o.Tags[0])
+	if res == nil {
+		return nil
+	}
+
+	op := &loldoc.Operation{
+		HTTPMethod:       method,
+		RequestPath:      path,
+		Description:      o.Summary,
+		ImplNotes:        o.Description,
+		RateLimitNotes:   o.RateLimitNotes,
+		RequiredTogether: o.RequiredTogether,
+	}
+
+	// region and platformId are declared first, like riot.go does.
+	for _, keyword := range []string{"region", "platformId"} {
+		if strings.Contains(path, "{"+keyword+"}") {
+			typ, err := patcher.Type(res.ID, "Region")
+			if err != nil {
+				return err
+			}
+			op.PathParams = append(op.PathParams, loldoc.Parameter{Name: keyword, Type: typ})
+		}
+	}
+
+	patch := patcher.ForOperation(res.ID, method, path)
+	op.MethodName = patch.Name
+	op.OverridedMapKey = patch.MapKey
+
+	for _, p := range o.Parameters {
+		param := loldoc.Parameter{
+			Name:        p.Name,
+			Description: p.Description,
+			Required:    p.Required,
+			Multiple:    p.Multiple,
+			MaxItems:    p.MaxItems,
+		}
+		if loldoc.IsRegion(param) {
+			if p.Name == "region" && p.Schema != nil {
+				i.addRegions(res, p.Schema.Enum)
+			}
+			continue
+		}
+		if p.Schema == nil {
+			return errors.Errorf("parameter %q does not have a schema", p.Name)
+		}
+
+		str, typ, err := i.typeOf(res, p.Schema)
+		if err != nil {
+			return errors.Wrapf(err, "parameter %q", p.Name)
+		}
+		if param.Enum = p.Schema.Enum; len(param.Enum) == 0 {
+			param.Enum = patcher.ParamEnum(p.Name)
+		}
+		if param.MaxItems == 0 {
+			param.MaxItems = p.Schema.MaxItems
+		}
+
+		switch p.In {
+		case "path":
+			if patched := patcher.PathParamType(p.Name, str); patched != str {
+				if typ, err = patcher.Type(res.ID, patched); err != nil {
+					return err
+				}
+			}
+			param.Type = typ
+			op.PathParams = append(op.PathParams, param)
+		case "query":
+			param.Type = typ
+			op.QueryParams = append(op.QueryParams, param)
+		default: // e.g. header for api key
+			logging.Debugf(i.c, "ignoring %s parameter %q", p.In, p.Name)
+		}
+	}
+
+	if rb := o.RequestBody; rb != nil {
+		mt, ok := rb.Content[jsonMediaType]
+		if !ok || mt.Schema == nil {
+			return errors.New("request body is not json")
+		}
+		_, typ, err := i.typeOf(res, mt.Schema)
+		if err != nil {
+			return errors.Wrap(err, "request body")
+		}
+		op.Body = &loldoc.Parameter{
+			Name:        "body",
+			Description: rb.Description,
+			Required:    rb.Required,
+			Type:        typ,
+		}
+	}
+
+	codes := make([]int, 0, len(o.Responses))
+	for code := range o.Responses {
+		n, err := strconv.Atoi(code)
+		if err != nil { // e.g. "default", "2XX"
+			logging.Debugf(i.c, "ignoring response %q", code)
+			continue
+		}
+		codes = append(codes, n)
+	}
+	sort.Ints(codes)
+	for _, code := range codes {
+		r := o.Responses[strconv.Itoa(code)]
+		if code >= 300 {
+			op.ResponseErrors = append(op.ResponseErrors, loldoc.NewResponseError(code, r.Description))
+			continue
+		}
+		if mt, ok := r.Content[jsonMediaType]; ok && mt.Schema != nil && op.OrigReturnType == nil {
+			_, typ, err := i.typeOf(res, mt.Schema)
+			if err != nil {
+				return errors.Wrapf(err, "response %d", code)
+			}
+			op.OrigReturnType = typ
+		}
+	}
+
+	servers := item.Servers
+	if len(servers) == 0 {
+		servers = i.spec.Servers
+	}
+	for _, s := range servers {
+		if v, ok := s.Variables["region"]; ok {
+			i.addRegions(res, v.Enum)
+		}
+	}
+
+	res.AddOperation(op)
+	return nil
+}
+
+func (i *importer) addRegions(res *loldoc.Resource, regions []string) {
+	if i.regions == nil {
+		i.regions = make(map[*loldoc.Resource][]string)
+	}
+	seen := make(map[string]bool)
+	for _, r := range i.regions[res] {
+		seen[r] = true
+	}
+	for _, r := range regions {
+		if r = strings.ToUpper(r); !seen[r] {
+			seen[r] = true
+			i.regions[res] = append(i.regions[res], r)
+		}
+	}
+}
+
+func (i *importer) addClass(name string) (err error) {
+	res := i.owners[name]
+	s := i.spec.Components.Schemas[name]
+
+	cls := loldoc.Schema{
+		Description: s.Description,
+		OrigName:    riotName(name, s),
+		Fields:      make([]loldoc.Field, 0, len(s.Properties)),
+	}
+	cls.StructName = patcher.StructName(res.ID, cls.OrigName)
+	if cls.Example, err = compactJSON(s.Example); err != nil {
+		return errors.Wrap(err, "example")
+	}
+
+	for _, p := range s.Properties {
+		str, typ, err := i.typeOf(res, p.Schema)
+		if err != nil {
+			return errors.Wrapf(err, "property %q", p.Name)
+		}
+		if patched := patcher.FieldTypeString(res.ID, cls.OrigName, p.Name, str); patched != str {
+			if typ, err = patcher.Type(res.ID, patched); err != nil {
+				return err
+			}
+		}
+		f := loldoc.NewField(p.Name, typ, p.Schema.Description)
+		if len(p.Schema.Example) != 0 {
+			if f.Example, err = compactJSON(p.Schema.Example); err != nil {
+				return errors.Wrapf(err, "example of property %q", p.Name)
+			}
+		}
+		cls.Fields = append(cls.Fields, f)
+	}
+
+	res.AddDefinition(cls)
+	return nil
+}
+
+// compactJSON returns empty string for an empty value.
+func compactJSON(raw json.RawMessage) (string, error) {
+	if len(raw) == 0 {
+		return "", nil
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, raw); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// typeOf returns type of s as a type string of riot document (e.g. "List[long]") and a go type.
+// The type string is used to look up patches.
+func (i *importer) typeOf(res *loldoc.Resource, s *Schema) (string, types.Type, error) {
+	if s.Ref != "" {
+		return i.refTypeOf(res, s.Ref)
+	}
+
+	switch s.Type {
+	case "array":
+		if s.Items == nil {
+			return "", nil, errors.New("array without items")
+		}
+		str, elem, err := i.typeOf(res, s.Items)
+		if err != nil {
+			return "", nil, err
+		}
+		return "List[" + str + "]", types.NewSlice(elem), nil
+
+	case "object":
+		if s.AdditionalProperties == nil {
+			return "", nil, errors.New("inline object schema is not supported. declare it in components")
+		}
+		// keys of json objects are always strings, and are converted by patches (mapKey).
+		str, elem, err := i.typeOf(res, s.AdditionalProperties)
+		if err != nil {
+			return "", nil, err
+		}
+		return "Map[string, " + str + "]", types.NewMap(types.Typ[types.String], elem), nil
+	}
+
+	str := ""
+	switch s.Type {
+	case "integer":
+		str = "int"
+		if s.Format == "int64" {
+			str = "long"
+		}
+	case "number":
+		str = "double"
+		if s.Format == "float" {
+			str = "float"
+		}
+	case "boolean":
+		str = "boolean"
+	case "string":
+		str = "string"
+	default:
+		return "", nil, errors.Errorf("unsupported schema type %q", s.Type)
+	}
+	typ, err := patcher.Type(res.ID, str)
+	return str, typ, err
+}
+
+func (i *importer) refTypeOf(res *loldoc.Resource, ref string) (string, types.Type, error) {
+	if !strings.HasPrefix(ref, schemaRefPrefix) {
+		return "", nil, errors.Errorf("unsupported reference %q", ref)
+	}
+	name := ref[len(schemaRefPrefix):]
+	s, ok := i.spec.Components.Schemas[name]
+	if !ok {
+		return "", nil, errors.Errorf("unknown schema %q", name)
+	}
+
+	if _, ok := handwritten[name]; ok {
+		typ, err := patcher.Type(res.ID, name)
+		return name, typ, err
+	}
+	if s.Type != "object" || s.AdditionalProperties != nil || len(s.OneOf) != 0 { // alias
+		return i.typeOf(res, s)
+	}
+
+	owner, ok := i.owners[name]
+	if !ok {
+		owner = res
+		if s.RiotResource != "" {
+			// tags of older versions exported side by side, or resource ids.
+			if owner, ok = i.byTag[s.RiotResource]; !ok {
+				owner = i.byID[s.RiotResource]
+			}
+		} else if idx := strings.LastIndex(name, "."); idx != -1 {
+			owner = i.resource(name[:idx])
+		}
+		if owner != nil {
+			i.owners[name] = owner
+			i.pending = append(i.pending, name)
+		}
+	}
+	if owner == nil {
+		return "", nil, errors.Errorf("schema %q is declared in a filtered resource", name)
+	}
+
+	origName := riotName(name, s)
+	return origName, patcher.ClassType(owner.ID, origName), nil
+}
+
+func riotName(key string, s *Schema) string {
+	if s.RiotName != "" {
+		return s.RiotName
+	}
+	if idx := strings.LastIndex(key, "."); idx != -1 { // e.g. "summoner-v1.4.SummonerDto"
+		return key[idx+1:]
+	}
+	return key
+}
@@ -0,0 +1,207 @@
+// Package openapi converts api documents between loldoc and OpenAPI 3.1.
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// Version is the OpenAPI version of documents created by FromDoc.
+const Version = "3.1.0"
+
+// Document is a subset of OpenAPI document used to describe riot api.
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Servers    []*Server            `json:"servers,omitempty"`
+	Tags       []*Tag               `json:"tags,omitempty"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+}
+
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// Tag groups operations of a resource.
+type Tag struct {
+	// Name is the resource id (e.g. "summoner").
+	Name string `json:"name"`
+	// Version is the resource version (e.g. "v1.4").
+	Version string `json:"x-riot-version,omitempty"`
+	// Regions is regions supporting the resource (e.g. ["BR", "EUNE"]).
+	Regions []string `json:"x-riot-regions,omitempty"`
+}
+
+type PathItem struct {
+	Servers []*Server  `json:"servers,omitempty"`
+	Get     *Operation `json:"get,omitempty"`
+	Post    *Operation `json:"post,omitempty"`
+	Put     *Operation `json:"put,omitempty"`
+	Delete  *Operation `json:"delete,omitempty"`
+}
+
+type Server struct {
+	URL       string                     `json:"url"`
+	Variables map[string]*ServerVariable `json:"variables,omitempty"`
+}
+
+type ServerVariable struct {
+	Enum    []string `json:"enum,omitempty"`
+	Default string   `json:"default"`
+}
+
+type Operation struct {
+	OperationID string                `json:"operationId"`
+	Tags        []string              `json:"tags,omitempty"`
+	Summary     string                `json:"summary,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Parameters  []*Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*Response  `json:"responses"`
+	Security    []map[string][]string `json:"security"`
+
+	// RateLimitNotes is the "Rate Limit Notes" block of riot document.
+	RateLimitNotes string `json:"x-riot-rate-limit-notes,omitempty"`
+	// RequiredTogether is groups of parameters which must be set together.
+	RequiredTogether [][]string `json:"x-riot-required-together,omitempty"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"` // "path" or "query"
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Style       string  `json:"style,omitempty"`
+	Explode     *bool   `json:"explode,omitempty"`
+	Schema      *Schema `json:"schema"`
+
+	// Multiple is true if value is a comma-separated list of Schema.Enum.
+	Multiple bool `json:"x-riot-multiple,omitempty"`
+	// MaxItems is the maximum length of a list, which may be comma-separated string.
+	MaxItems int `json:"x-riot-max-items,omitempty"`
+}
+
+type RequestBody struct {
+	Description string                `json:"description,omitempty"`
+	Required    bool                  `json:"required,omitempty"`
+	Content     map[string]*MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas,omitempty"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type SecurityScheme struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+	In   string `json:"in"`
+}
+
+// Schema is a subset of JSON schema.
+type Schema struct {
+	Ref         string   `json:"$ref,omitempty"`
+	Type        string   `json:"type,omitempty"`
+	Format      string   `json:"format,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Enum        []string `json:"enum,omitempty"`
+	Pattern     string   `json:"pattern,omitempty"`
+	MaxItems    int      `json:"maxItems,omitempty"`
+
+	Items                *Schema    `json:"items,omitempty"`
+	Properties           Properties `json:"properties,omitempty"`
+	AdditionalProperties *Schema    `json:"additionalProperties,omitempty"`
+	PropertyNames        *Schema    `json:"propertyNames,omitempty"`
+	OneOf                []*Schema  `json:"oneOf,omitempty"`
+
+	// Example is a documented example of a class, or a sample value of a property.
+	Example json.RawMessage `json:"example,omitempty"`
+
+	// RiotName is the original class name in riot document.
+	RiotName string `json:"x-riot-name,omitempty"`
+	// RiotResource is the id of resource declaring the class, or the tag of an older version exported side by side.
+	RiotResource string `json:"x-riot-resource,omitempty"`
+}
+
+// Property is a named property of an object schema.
+type Property struct {
+	Name   string
+	Schema *Schema
+}
+
+// Properties keeps order of properties, which is the order of fields in riot document.
+type Properties []Property
+
+// MarshalJSON encodes properties as an object, in order.
+func (ps Properties) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, p := range ps {
+		if i != 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(p.Name)
+		if err != nil {
+			return nil, err
+		}
+		schema, err := json.Marshal(p.Schema)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(schema)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON decodes an object, keeping order of its keys.
+func (ps *Properties) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if t, err := dec.Token(); err != nil {
+		return err
+	} else if t != json.Delim('{') {
+		return errors.Errorf("openapi: properties must be an object, got %v", t)
+	}
+
+	*ps = nil
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		name, _ := t.(string)
+
+		p := Property{Name: name, Schema: &Schema{}}
+		if err := dec.Decode(p.Schema); err != nil {
+			return errors.Wrapf(err, "openapi: property %q", name)
+		}
+		*ps = append(*ps, p)
+	}
+	_, err := dec.Token() // '}'
+	return err
+}
+
+// Get returns schema of a property named name, or nil.
+func (ps Properties) Get(name string) *Schema {
+	for _, p := range ps {
+		if p.Name == name {
+			return p.Schema
+		}
+	}
+	return nil
+}
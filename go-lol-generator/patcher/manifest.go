@@ -0,0 +1,346 @@
+package patcher
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/types"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+// ManifestVersion is the version of patch manifest supported by this package.
+const ManifestVersion = 1
+
+// Patches is a patch manifest. See patches.json for an example.
+type Patches struct {
+	Version int `json:"version"`
+	// map[parameter name]type
+	PathParamTypes map[string]string `json:"pathParamTypes"`
+	// map[parameter name]allowed values, used if the document does not list them.
+	ParamEnums map[string][]string  `json:"paramEnums"`
+	Resources  map[string]*ResPatch `json:"resources"`
+
+	used    map[entry]bool
+	missing []*ErrPatchRequired
+}
+
+type ResPatch struct {
+	// Name of the resource in go identifiers (e.g. "ChampionMastery" for ChampionMasteryAPI).
+	// Defaults to the id in camel case.
+	Name string `json:"name"`
+	// Routing is how requests are routed to hosts of riotgames.com: "platform" (default),
+	// "cluster" for continental clusters, or "fixed" for a host of its own.
+	Routing string `json:"routing"`
+	// map[path suffix]Operation
+	Operations map[string]OpPatch    `json:"operations"`
+	Classes    map[string]ClassPatch `json:"classes"`
+	// map["Class.field"]type
+	FieldTypes map[string]string `json:"fieldTypes"`
+}
+
+// OpPatch represets a predeclared operation info.
+type OpPatch struct {
+	// Method name on client.
+	//
+	// Required
+	Name string
+
+	// Override map key in return value.
+	// Patch will panic if original return value is not map.
+	MapKey types.BasicKind
+}
+
+type ClassPatch struct {
+	Name string `json:"name"`
+	// Distinct keeps the class as its own type, even if it's identical to classes of other resources.
+	Distinct bool `json:"distinct"`
+	// SharedName names the type shared with identical classes. See loldoc.Dedup.
+	SharedName string `json:"sharedName"`
+}
+
+var mapKeyKinds = map[string]types.BasicKind{
+	"int32":  types.Int32,
+	"int64":  types.Int64,
+	"string": types.String,
+}
+
+// UnmarshalJSON decodes {"name": "Summoners", "mapKey": "int64"}.
+func (op *OpPatch) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Name   string `json:"name"`
+		MapKey string `json:"mapKey"`
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&raw); err != nil {
+		return err
+	}
+
+	op.Name = raw.Name
+	op.MapKey = types.Invalid
+	if raw.MapKey != "" {
+		kind, ok := mapKeyKinds[raw.MapKey]
+		if !ok {
+			return errors.Errorf("unsupported map key type %q", raw.MapKey)
+		}
+		op.MapKey = kind
+	}
+	return nil
+}
+
+// ErrInvalidManifest lists every problem found while validating a manifest.
+type ErrInvalidManifest []string
+
+func (e ErrInvalidManifest) Error() string {
+	return "patcher: invalid manifest:\n\t" + strings.Join(e, "\n\t")
+}
+
+// entry identifies a patch in manifest.
+type entry struct {
+	res, section, key string
+}
+
+func (e entry) String() string {
+	switch {
+	case e.res == "":
+		return fmt.Sprintf("%s[%q]", e.section, e.key)
+	case e.section == "":
+		return fmt.Sprintf("resources[%q]", e.res)
+	default:
+		return fmt.Sprintf("resources[%q].%s[%q]", e.res, e.section, e.key)
+	}
+}
+
+var patches *Patches
+
+func current() *Patches {
+	if patches == nil {
+		panic("patcher: no manifest is loaded. LoadFile or Use must be called first")
+	}
+	return patches
+}
+
+// Load reads and validates a patch manifest.
+func Load(r io.Reader) (*Patches, error) {
+	p := &Patches{}
+
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(p); err != nil {
+		return nil, errors.Wrap(err, "patcher: failed to decode manifest")
+	}
+
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// LoadFile loads the patch manifest stored in filename and makes it current.
+func LoadFile(filename string) error {
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	p, err := Load(f)
+	if err != nil {
+		return errors.Wrapf(err, "%s", filename)
+	}
+	Use(p)
+	return nil
+}
+
+// Use makes p the current manifest, and resets recorded usages and misses.
+func Use(p *Patches) {
+	p.used = make(map[entry]bool)
+	p.missing = nil
+	patches = p
+}
+
+// Missing returns patches which were required, but not found in current manifest.
+func Missing() ErrPatchesRequired {
+	return append(ErrPatchesRequired(nil), current().missing...)
+}
+
+// Unused returns entries of current manifest which were never looked up.
+func Unused() []string {
+	p := current()
+
+	var unused []string
+	for _, e := range p.entries() {
+		if !p.used[e] {
+			unused = append(unused, e.String())
+		}
+	}
+	sort.Strings(unused)
+	return unused
+}
+
+func (p *Patches) use(e entry) {
+	p.used[e] = true
+}
+
+func (p *Patches) require(err *ErrPatchRequired) {
+	for _, e := range p.missing {
+		if *e == *err {
+			return
+		}
+	}
+	p.missing = append(p.missing, err)
+}
+
+func (p *Patches) entries() []entry {
+	var es []entry
+	for name := range p.PathParamTypes {
+		es = append(es, entry{section: "pathParamTypes", key: name})
+	}
+	for name := range p.ParamEnums {
+		es = append(es, entry{section: "paramEnums", key: name})
+	}
+	for id, rp := range p.Resources {
+		es = append(es, entry{res: id})
+		for key := range rp.Operations {
+			es = append(es, entry{id, "operations", key})
+		}
+		for key := range rp.Classes {
+			es = append(es, entry{id, "classes", key})
+		}
+		for key := range rp.FieldTypes {
+			es = append(es, entry{id, "fieldTypes", key})
+		}
+	}
+	return es
+}
+
+var httpMethods = map[string]bool{
+	"GET":    true,
+	"POST":   true,
+	"PUT":    true,
+	"DELETE": true,
+}
+
+func (p *Patches) validate() error {
+	var errs ErrInvalidManifest
+	errorf := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Sprintf(format, args...))
+	}
+
+	if p.Version != ManifestVersion {
+		errorf("unsupported version %d (want %d)", p.Version, ManifestVersion)
+	}
+	if len(p.Resources) == 0 {
+		errorf("no resource")
+	}
+
+	for name, typ := range p.PathParamTypes {
+		if typ == "" {
+			errorf("%s: empty type", entry{section: "pathParamTypes", key: name})
+		}
+	}
+
+	for name, vals := range p.ParamEnums {
+		if len(vals) == 0 {
+			errorf("%s: no value", entry{section: "paramEnums", key: name})
+		}
+		for _, v := range vals {
+			if v == "" || strings.Contains(v, ",") {
+				errorf("%s: invalid value %q", entry{section: "paramEnums", key: name}, v)
+			}
+		}
+	}
+
+	methodNames := make(map[string]entry)
+	structNames := make(map[string]entry)
+	for id, rp := range p.Resources {
+		if rp == nil {
+			errorf("%s: null", entry{res: id})
+			continue
+		}
+
+		if rp.Name != "" && !isExportedIdent(rp.Name) {
+			errorf("%s: name %q is not an exported identifier", entry{res: id}, rp.Name)
+		}
+		switch rp.Routing {
+		case "", "platform", "cluster", "fixed":
+		default:
+			errorf("%s: unknown routing %q", entry{res: id}, rp.Routing)
+		}
+
+		for key, op := range rp.Operations {
+			e := entry{id, "operations", key}
+			if i := strings.IndexByte(key, ' '); i != -1 {
+				if !httpMethods[key[:i]] {
+					errorf("%s: unknown http method %q", e, key[:i])
+				}
+				key = key[i+1:]
+			}
+			if key == "" {
+				errorf("%s: empty path suffix", e)
+			}
+
+			if !isExportedIdent(op.Name) {
+				errorf("%s: name %q is not an exported identifier", e, op.Name)
+			} else if prev, ok := methodNames[op.Name]; ok {
+				errorf("%s: name %q is already used by %s", e, op.Name, prev)
+			} else {
+				methodNames[op.Name] = e
+			}
+		}
+
+		for key, cp := range rp.Classes {
+			e := entry{id, "classes", key}
+			if !isExportedIdent(cp.Name) {
+				errorf("%s: name %q is not an exported identifier", e, cp.Name)
+			} else if prev, ok := structNames[cp.Name]; ok {
+				errorf("%s: name %q is already used by %s", e, cp.Name, prev)
+			} else {
+				structNames[cp.Name] = e
+			}
+			if cp.SharedName != "" && !isExportedIdent(cp.SharedName) {
+				errorf("%s: shared name %q is not an exported identifier", e, cp.SharedName)
+			}
+			if cp.SharedName != "" && cp.Distinct {
+				errorf("%s: distinct class cannot have shared name", e)
+			}
+		}
+
+		for key, typ := range rp.FieldTypes {
+			e := entry{id, "fieldTypes", key}
+			if parts := strings.Split(key, "."); len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+				errorf("%s: key must be formatted as Class.field", e)
+			}
+			if typ == "" {
+				errorf("%s: empty type", e)
+			}
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	sort.Strings(errs)
+	return errs
+}
+
+func isExportedIdent(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		if i == 0 && !unicode.IsUpper(r) {
+			return false
+		}
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,91 @@
+package patcher
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestManifest(t *testing.T) {
+	Convey("Load", t, func() {
+		Convey("Accepts patches.json", func() {
+			So(LoadFile("patches.json"), ShouldBeNil)
+		})
+
+		Convey("Rejects unknown fields", func() {
+			_, err := Load(strings.NewReader(`{"version": 1, "resources": {"x": {"operation": {}}}}`))
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Reports every invalid entry", func() {
+			_, err := Load(strings.NewReader(`{
+				"version": 2,
+				"resources": {
+					"a": {
+						"operations": {
+							"/x":        {"name": "lower"},
+							"PATCH /y":  {"name": "Y"},
+							"/z":        {"name": "Z", "mapKey": "int64"}
+						},
+						"classes": {"ADto": {"name": "Z"}, "BDto": {"name": "B"}},
+						"fieldTypes": {"ADto": "int"}
+					},
+					"b": {
+						"operations": {"/z": {"name": "Z"}},
+						"classes": {"BDto": {"name": "B"}}
+					}
+				}
+			}`))
+			So(err, ShouldHaveSameTypeAs, ErrInvalidManifest{})
+			So(err, ShouldHaveLength, 6)
+		})
+
+		Convey("Rejects shared name of distinct class", func() {
+			_, err := Load(strings.NewReader(`{"version": 1, "resources": {"a": {"classes": {"X": {"name": "X", "distinct": true, "sharedName": "Y"}}}}}`))
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Rejects resource name which is not an exported identifier", func() {
+			_, err := Load(strings.NewReader(`{"version": 1, "resources": {"a": {"name": "lower"}}}`))
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Rejects unknown routing", func() {
+			_, err := Load(strings.NewReader(`{"version": 1, "resources": {"a": {"routing": "continental"}}}`))
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Rejects unsupported map key", func() {
+			_, err := Load(strings.NewReader(`{"version": 1, "resources": {"a": {"operations": {"/x": {"name": "X", "mapKey": "float64"}}}}}`))
+			So(err, ShouldNotBeNil)
+		})
+	})
+
+	Convey("Missing and Unused", t, func() {
+		p, err := Load(strings.NewReader(`{
+			"version": 1,
+			"resources": {
+				"a": {
+					"operations": {"/x": {"name": "X"}, "/y": {"name": "Y"}},
+					"classes": {"XDto": {"name": "X"}}
+				}
+			}
+		}`))
+		So(err, ShouldBeNil)
+		Use(p)
+
+		So(OperationName("a", "GET", "/api/x"), ShouldEqual, "X")
+		So(StructName("a", "XDto"), ShouldEqual, "X")
+		So(StructName("a", "YDto"), ShouldEqual, "YDto")
+		So(StructName("a", "YDto"), ShouldEqual, "YDto")
+		So(OperationName("b", "GET", "/api/z"), ShouldEqual, "")
+		So(StructName("b", "ZDto"), ShouldEqual, "ZDto")
+
+		So(Missing(), ShouldResemble, ErrPatchesRequired{
+			{ResID: "a", For: `class "YDto"`},
+			{ResID: "b"},
+		})
+		So(Unused(), ShouldResemble, []string{`resources["a"].operations["/y"]`})
+	})
+}
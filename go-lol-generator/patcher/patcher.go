@@ -11,6 +11,7 @@ import (
 	"github.com/pkg/errors"
 )
 
+// ErrPatchRequired describes a patch missing from the manifest.
 type ErrPatchRequired struct {
 	ResID string
 	For   string
@@ -29,53 +30,99 @@ func (e ErrPatchRequired) Error() string {
 	return fmt.Sprintf("patcher: override required for %s in resource %q", e.For, e.ResID)
 }
 
-func forResource(id string) (*ResPatch, error) {
-	rp, ok := overrides.Resources[id]
+// ErrPatchesRequired lists every patch found to be missing in a run.
+type ErrPatchesRequired []*ErrPatchRequired
+
+func (es ErrPatchesRequired) Error() string {
+	msgs := make([]string, 0, len(es)+1)
+	msgs = append(msgs, fmt.Sprintf("patcher: %d override(s) required", len(es)))
+	for _, e := range es {
+		msgs = append(msgs, strings.TrimSpace(e.Error()))
+	}
+	return strings.Join(msgs, "\n\t")
+}
+
+func forResource(id string) (*ResPatch, bool) {
+	rp, ok := current().Resources[id]
 	if !ok {
-		return nil, patchRequired(id, "")
+		current().require(patchRequired(id, ""))
+		return nil, false
 	}
-	return &rp, nil
+	current().use(entry{res: id})
+	return rp, true
 }
 
-func ForClass(resID, clsName string) (*ClassPatch, error) {
-	rp, err := forResource(resID)
-	if err != nil {
-		return nil, err
+// ResourceName returns the name of a resource in go identifiers, or "" if the manifest does not name it.
+func ResourceName(id string) string {
+	rp, ok := current().Resources[id]
+	if !ok || rp == nil {
+		return ""
 	}
-	cp, err := rp.Class(clsName)
-	if err != nil {
-		return nil, err
+	return rp.Name
+}
+
+// ResourceRouting returns routing of a resource, or "" if the manifest does not set it.
+func ResourceRouting(id string) string {
+	rp, ok := current().Resources[id]
+	if !ok || rp == nil {
+		return ""
 	}
-	return cp, nil
+	return rp.Routing
 }
 
-func StructName(resID, clsName string) (string, error) {
-	cp, err := ForClass(resID, clsName)
-	if err != nil {
-		return "", err
+// ForClass returns a patch for a class.
+//
+// If the manifest does not have one, the miss is recorded (See Missing)
+// and a patch keeping the original name is returned, so parsing can go on
+// and report every missing patch at once.
+func ForClass(resID, clsName string) *ClassPatch {
+	rp, ok := forResource(resID)
+	if !ok {
+		return &ClassPatch{Name: clsName}
 	}
-	return cp.Name, nil
+	cp, ok := rp.Classes[clsName]
+	if !ok {
+		current().require(patchRequired(resID, "class %q", clsName))
+		return &ClassPatch{Name: clsName}
+	}
+	current().use(entry{resID, "classes", clsName})
+	return &cp
 }
 
-func ForOperation(resID, opPath string) (*OpPatch, error) {
-	rp, err := forResource(resID)
-	if err != nil {
-		return nil, err
+func StructName(resID, clsName string) string {
+	return ForClass(resID, clsName).Name
+}
+
+// ForOperation finds a patch for an operation.
+//
+// A key of ResPatch.Operations is a path suffix, optionally prefixed by
+// http method (e.g. "PUT /code/{tournamentCode}") for operations sharing a path.
+// A key with http method takes precedence.
+//
+// Like ForClass, a miss is recorded and an empty patch is returned.
+func ForOperation(resID, httpMethod, opPath string) *OpPatch {
+	rp, ok := forResource(resID)
+	if !ok {
+		return &OpPatch{}
 	}
-	for pathSuffix, pp := range rp.Operations {
-		if strings.HasSuffix(opPath, pathSuffix) {
-			return &pp, nil
+	for key, pp := range rp.Operations {
+		if strings.HasPrefix(key, httpMethod+" ") && strings.HasSuffix(opPath, key[len(httpMethod)+1:]) {
+			current().use(entry{resID, "operations", key})
+			return &pp
 		}
 	}
-	return nil, patchRequired(resID, "operation %q", opPath)
+	for key, pp := range rp.Operations {
+		if !strings.Contains(key, " ") && strings.HasSuffix(opPath, key) {
+			current().use(entry{resID, "operations", key})
+			return &pp
+		}
+	}
+	current().require(patchRequired(resID, "operation %s %q", httpMethod, opPath))
+	return &OpPatch{}
 }
 
-func OperationName(resID, opPath string) (string, error) {
-	op, err := ForOperation(resID, opPath)
-	if err != nil {
-		return "", err
-	}
-	return op.Name, nil
+func OperationName(resID, httpMethod, opPath string) string {
+	return ForOperation(resID, httpMethod, opPath).Name
 }
 
 var javaPrimitives = map[string]types.BasicKind{
@@ -99,6 +146,11 @@ func Type(resID, s string) (types.Type, error) {
 		return nil, errors.Errorf("cannot parse empty string as types.Type\n")
 	}
 
+	// Region is declared by hand in package lol.
+	if s == "Region" {
+		return types.NewNamed(types.NewTypeName(token.NoPos, nil, s, nil), nil, nil), nil
+	}
+
 	if strings.HasPrefix(s, "List[") && strings.HasSuffix(s, "]") {
 		elem, err := Type(resID, s[5:len(s)-1])
 		if err != nil {
@@ -135,53 +187,44 @@ func Type(resID, s string) (types.Type, error) {
 		return types.NewMap(key, elem), nil
 	}
 
-	return ClassType(resID, s)
+	return ClassType(resID, s), nil
 }
 
-func ClassType(resID, clsName string) (types.Type, error) {
-	switch resID {
-	case "lol-static-data":
-		if clsName == "SpellRange" {
-			goto ret
-		}
-	}
-
-	{
-		cp, err := ForClass(resID, clsName)
-		if err != nil {
-			return nil, errors.Wrapf(err, "unknown type %q\n", clsName)
-		}
-		clsName = cp.Name
-		goto ret
-	}
-
-ret:
-	return types.NewPointer(types.NewNamed(types.NewTypeName(token.NoPos, nil, clsName, nil), nil, nil)), nil
+func ClassType(resID, clsName string) types.Type {
+	clsName = StructName(resID, clsName)
+	return types.NewPointer(types.NewNamed(types.NewTypeName(token.NoPos, nil, clsName, nil), nil, nil))
 }
 
+// PathParamType returns type string of a path parameter, which is patched if required.
 func PathParamType(paramName, typeStr string) string {
-	switch paramName {
-	case "summonerIds":
-		return "List[long]"
-	case "summonerNames":
-		return "List[string]"
+	if t, ok := current().PathParamTypes[paramName]; ok {
+		current().use(entry{section: "pathParamTypes", key: paramName})
+		return t
 	}
 	return typeStr
 }
 
-func FieldTypeString(resID, clsName, rawFieldName, typeStr string) string {
-	if resID == "lol-static-data" {
-		switch clsName {
-		case "SummonerSpellDto", "ChampionSpellDto":
-			switch rawFieldName {
-			case "effect": // typeStr == List[object]
-				return "List[List[double]]"
-			case "range":
-				return "SpellRange"
-			}
-		}
+// ParamEnum returns allowed values of a parameter declared in the manifest, or nil.
+func ParamEnum(paramName string) []string {
+	vals, ok := current().ParamEnums[paramName]
+	if !ok {
+		return nil
 	}
+	current().use(entry{section: "paramEnums", key: paramName})
+	return append([]string(nil), vals...)
+}
 
+// FieldTypeString returns type string of a field, which is patched if required.
+func FieldTypeString(resID, clsName, rawFieldName, typeStr string) string {
+	rp, ok := current().Resources[resID]
+	if !ok {
+		return typeStr
+	}
+	key := clsName + "." + rawFieldName
+	if t, ok := rp.FieldTypes[key]; ok {
+		current().use(entry{resID, "fieldTypes", key})
+		return t
+	}
 	return typeStr
 }
 
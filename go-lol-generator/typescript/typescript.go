@@ -0,0 +1,192 @@
+// Package typescript writes TypeScript declarations of response classes in a loldoc.Doc.
+//
+// Declarations describe JSON encoded from types of package lol, so that
+// clients of a server passing them through stay in sync with lol.generated.go.
+package typescript
+
+import (
+	"bytes"
+	"fmt"
+	"go/types"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/kdy1997/go-lol/go-lol-generator/loldoc"
+)
+
+// handwritten is declarations of types which are declared by hand in package lol.
+var handwritten = map[string]string{
+	// SpellRange is encoded by encoding/json without a custom MarshalJSON.
+	"SpellRange": `/** Range of a spell. Self is true if the range is "self". */
+export interface SpellRange {
+  Self: boolean;
+  Ranges: number[] | null;
+}
+`,
+	"Region": `/** Region name, encoded like "NA". */
+export type Region = string;
+`,
+}
+
+// Enum returns values listed in description of a field, or nil if there is none.
+func Enum(description string) []string {
+	return loldoc.LegalValues(description)
+}
+
+type writer struct {
+	bytes.Buffer
+	shared map[string]bool
+	used   map[string]bool // handwritten types referenced by fields
+}
+
+// Generate writes declarations of every response class of doc.
+// Classes merged by loldoc.Dedup are declared once, and old names are declared as aliases.
+func Generate(doc *loldoc.Doc) []byte {
+	w := &writer{
+		shared: make(map[string]bool),
+		used:   make(map[string]bool),
+	}
+	w.WriteString("// Generated by go-lol-generator. DO NOT EDIT.\n")
+
+	for _, res := range doc.Resources {
+		for _, s := range res.SortedDefinitions() {
+			w.class(doc, s)
+		}
+	}
+
+	for _, name := range []string{"Region", "SpellRange"} {
+		if w.used[name] {
+			w.WriteString("\n" + handwritten[name])
+		}
+	}
+	return w.Bytes()
+}
+
+func (w *writer) class(doc *loldoc.Doc, s loldoc.Schema) {
+	name := s.StructName
+	if s.SharedName != "" {
+		name = s.SharedName
+		if s.StructName != s.SharedName {
+			w.WriteString("\n")
+			w.comment("", fmt.Sprintf("@deprecated Use %s, which is shared by identical classes of other resources.", s.SharedName))
+			fmt.Fprintf(w, "export type %s = %s;\n", s.StructName, s.SharedName)
+		}
+		if w.shared[name] {
+			return
+		}
+		w.shared[name] = true
+	}
+
+	var enums []string
+	w.WriteString("\n")
+	cmt := s.Description
+	if cmt != "" {
+		cmt += "\n\n"
+	}
+	if s.SharedName == "" {
+		cmt += fmt.Sprintf("resource: %q, original name: %q", s.ResID(), s.OrigName)
+	} else {
+		cmt += "shared by identical classes:"
+		for _, res := range doc.Resources {
+			for _, other := range res.SortedDefinitions() {
+				if other.SharedName == s.SharedName {
+					cmt += fmt.Sprintf("\n - resource: %q, original name: %q", other.ResID(), other.OrigName)
+				}
+			}
+		}
+	}
+	w.comment("", cmt)
+	fmt.Fprintf(w, "export interface %s {\n", name)
+	for _, f := range s.Fields {
+		typ := w.typeOf(f.Type)
+		if vals := Enum(f.Description); vals != nil && f.Type == types.Typ[types.String] {
+			typ = name + f.GoName()
+			enums = append(enums, typ+" = "+union(vals))
+		}
+		w.comment("  ", f.Description)
+		fmt.Fprintf(w, "  %s: %s;\n", propertyName(f.OrigName()), typ)
+	}
+	w.WriteString("}\n")
+
+	for _, e := range enums {
+		fmt.Fprintf(w, "\nexport type %s;\n", e)
+	}
+}
+
+func (w *writer) comment(indent, cmt string) {
+	if cmt == "" {
+		return
+	}
+	lines := strings.Split(strings.Replace(cmt, "*/", "*\\/", -1), "\n")
+	if len(lines) == 1 {
+		fmt.Fprintf(w, "%s/** %s */\n", indent, lines[0])
+		return
+	}
+	fmt.Fprintf(w, "%s/**\n", indent)
+	for _, line := range lines {
+		fmt.Fprintf(w, "%s *%s\n", indent, prefixSpace(line))
+	}
+	fmt.Fprintf(w, "%s */\n", indent)
+}
+
+func prefixSpace(s string) string {
+	if s == "" {
+		return ""
+	}
+	return " " + s
+}
+
+func union(vals []string) string {
+	quoted := make([]string, len(vals))
+	for i, v := range vals {
+		quoted[i] = strconv.Quote(v)
+	}
+	return strings.Join(quoted, " | ")
+}
+
+var identifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+func propertyName(name string) string {
+	if identifier.MatchString(name) {
+		return name
+	}
+	return strconv.Quote(name)
+}
+
+// typeOf returns TypeScript type of JSON encoded from t.
+// nil pointers, slices and maps are encoded as null, but elements of them are assumed not to be null.
+func (w *writer) typeOf(t types.Type) string {
+	switch t := t.(type) {
+	case *types.Pointer, *types.Slice, *types.Map:
+		return w.elemTypeOf(t) + " | null"
+	}
+	return w.elemTypeOf(t)
+}
+
+func (w *writer) elemTypeOf(t types.Type) string {
+	switch t := t.(type) {
+	case *types.Basic:
+		switch {
+		case t.Info()&types.IsBoolean != 0:
+			return "boolean"
+		case t.Info()&types.IsNumeric != 0:
+			return "number"
+		case t.Info()&types.IsString != 0:
+			return "string"
+		}
+	case *types.Named:
+		name := t.Obj().Name()
+		if _, ok := handwritten[name]; ok {
+			w.used[name] = true
+		}
+		return name
+	case *types.Pointer:
+		return w.elemTypeOf(t.Elem())
+	case *types.Slice:
+		return w.elemTypeOf(t.Elem()) + "[]"
+	case *types.Map:
+		return "{ [key: string]: " + w.elemTypeOf(t.Elem()) + " }"
+	}
+	panic(fmt.Sprintf("typescript: unsupported type %v", t))
+}
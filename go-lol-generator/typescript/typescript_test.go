@@ -0,0 +1,61 @@
+package typescript
+
+import (
+	"testing"
+
+	"github.com/kdy1997/go-lol/go-lol-generator/loldoc"
+	"github.com/kdy1997/go-lol/go-lol-generator/patcher"
+	"github.com/luci/luci-go/common/logging/memlogger"
+	. "github.com/smartystreets/goconvey/convey"
+	"golang.org/x/net/context"
+)
+
+func TestGenerate(t *testing.T) {
+	if err := patcher.LoadFile("../patcher/patches.json"); err != nil {
+		t.Fatal(err)
+	}
+	gqDoc, err := loldoc.OpenGoQueryDoc("../loldoc/methods.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := loldoc.Parse(memlogger.Use(context.Background()), gqDoc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loldoc.Dedup(doc)
+
+	Convey("Generate", t, func() {
+		src := string(Generate(doc))
+
+		Convey("Uses struct names and field docs", func() {
+			So(src, ShouldContainSubstring, `export interface Summoner {
+  /** Summoner ID. */
+  id: number;`)
+			So(src, ShouldContainSubstring, `  champions: ChampionStatus[] | null;`)
+			So(src, ShouldContainSubstring, `  participantFrames: { [key: string]: ParticipantFrame } | null;`)
+		})
+
+		Convey("Declares enums of legal values", func() {
+			So(src, ShouldContainSubstring, `  masteryTree: MasteryMasteryTree;`)
+			So(src, ShouldContainSubstring, `export type MasteryMasteryTree = "Cunning" | "Ferocity" | "Resolve";`)
+			So(src, ShouldNotContainSubstring, `"TOP(1)"`)
+		})
+
+		Convey("Declares shared classes once", func() {
+			So(src, ShouldContainSubstring, "export type CurrentGameObserver = Observer;")
+			So(src, ShouldContainSubstring, "export type FeaturedGameObserver = Observer;")
+			So(src, ShouldContainSubstring, " - resource: \"featured-games\", original name: \"Observer\"")
+		})
+
+		Convey("Declares handwritten types", func() {
+			So(src, ShouldContainSubstring, "export interface SpellRange {")
+		})
+	})
+
+	Convey("Enum", t, func() {
+		So(Enum("The game type (Legal values: CUSTOM_GAME, MATCHED_GAME, TUTORIAL_GAME)"), ShouldResemble, []string{"CUSTOM_GAME", "MATCHED_GAME", "TUTORIAL_GAME"})
+		So(Enum("Legal values: DUO, NONE"), ShouldResemble, []string{"DUO", "NONE"})
+		So(Enum("Player role (Legal values: DUO(1), SUPPORT(2))"), ShouldBeNil)
+		So(Enum("Summoner ID."), ShouldBeNil)
+	})
+}
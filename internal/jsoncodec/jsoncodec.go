@@ -0,0 +1,672 @@
+// Package jsoncodec is a runtime of JSON methods generated by go-lol-generator.
+//
+// Generated UnmarshalJSON and MarshalJSON methods use Lexer and Writer instead
+// of reflection. They follow encoding/json: object keys are matched exactly first,
+// then case-insensitively (See FoldKey).
+package jsoncodec
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// SyntaxError is reported by Lexer for malformed or unexpected input.
+type SyntaxError struct {
+	Offset int // offset of the input where the error occurred
+	Msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("jsoncodec: %s at offset %d", e.Msg, e.Offset)
+}
+
+// Lexer reads a JSON value token by token.
+// After the first error, every method returns zero value and More returns false.
+type Lexer struct {
+	data []byte
+	pos  int
+	err  error
+
+	// first is a stack of open objects and arrays, true if no element is read yet.
+	first []bool
+}
+
+// NewLexer creates a lexer reading data.
+func NewLexer(data []byte) *Lexer {
+	return &Lexer{data: data}
+}
+
+// Done returns the first error, or an error if data has more than one value.
+func (l *Lexer) Done() error {
+	if l.err == nil {
+		l.skipSpace()
+		if l.pos < len(l.data) {
+			l.errorf("unexpected %q after value", l.data[l.pos])
+		}
+	}
+	return l.err
+}
+
+// AddError records err if it is the first error.
+func (l *Lexer) AddError(err error) {
+	if l.err == nil && err != nil {
+		l.err = err
+	}
+}
+
+func (l *Lexer) errorf(format string, args ...interface{}) {
+	l.AddError(&SyntaxError{Offset: l.pos, Msg: fmt.Sprintf(format, args...)})
+}
+
+func (l *Lexer) skipSpace() {
+	for l.pos < len(l.data) {
+		switch l.data[l.pos] {
+		case ' ', '\t', '\n', '\r':
+			l.pos++
+		default:
+			return
+		}
+	}
+}
+
+// peek returns the first byte of the next token, or 0 at the end of data.
+func (l *Lexer) peek() byte {
+	l.skipSpace()
+	if l.err != nil || l.pos >= len(l.data) {
+		return 0
+	}
+	return l.data[l.pos]
+}
+
+// Null consumes null and returns true if the next value is null.
+// It also returns true after an error, so that callers stop reading.
+func (l *Lexer) Null() bool {
+	if l.err != nil {
+		return true
+	}
+	if l.peek() == 'n' && l.literal("null") {
+		return true
+	}
+	return false
+}
+
+func (l *Lexer) literal(lit string) bool {
+	if len(l.data)-l.pos < len(lit) || string(l.data[l.pos:l.pos+len(lit)]) != lit {
+		l.errorf("invalid literal")
+		return false
+	}
+	l.pos += len(lit)
+	return true
+}
+
+// Delim consumes one of '{', '}', '[' and ']'.
+func (l *Lexer) Delim(c byte) {
+	if got := l.peek(); got != c {
+		if l.err == nil {
+			l.errorf("expected %q but got %q", c, got)
+		}
+		return
+	}
+	l.pos++
+	switch c {
+	case '{', '[':
+		l.first = append(l.first, true)
+	case '}', ']':
+		if len(l.first) > 0 {
+			l.first = l.first[:len(l.first)-1]
+		}
+	}
+}
+
+// More consumes a separating comma and returns true if the current object
+// or array has more elements.
+func (l *Lexer) More() bool {
+	c := l.peek()
+	if l.err != nil || c == '}' || c == ']' {
+		return false
+	}
+	if len(l.first) == 0 {
+		l.errorf("unexpected element outside of object or array")
+		return false
+	}
+	first := &l.first[len(l.first)-1]
+	if *first {
+		*first = false
+		return true
+	}
+	if c != ',' {
+		l.errorf("expected ',' but got %q", c)
+		return false
+	}
+	l.pos++
+	return true
+}
+
+// Key reads an object key and the following colon.
+// The returned slice is valid until the next call.
+func (l *Lexer) Key() []byte {
+	key := l.bytes()
+	if c := l.peek(); c != ':' {
+		if l.err == nil {
+			l.errorf("expected ':' but got %q", c)
+		}
+		return nil
+	}
+	l.pos++
+	return key
+}
+
+// FoldKey returns the first of keys equal to key under Unicode case-folding, or "" if there is none.
+// Generated methods call it for keys without an exact match, as encoding/json does.
+func FoldKey(key []byte, keys []string) string {
+	s := string(key)
+	for _, k := range keys {
+		if strings.EqualFold(s, k) {
+			return k
+		}
+	}
+	return ""
+}
+
+// String reads a string.
+func (l *Lexer) String() string {
+	return string(l.bytes())
+}
+
+// bytes reads a string, without copying it if possible.
+func (l *Lexer) bytes() []byte {
+	if c := l.peek(); c != '"' {
+		if l.err == nil {
+			l.errorf("expected string but got %q", c)
+		}
+		return nil
+	}
+	l.pos++
+	start := l.pos
+	for i := start; i < len(l.data); i++ {
+		switch c := l.data[i]; {
+		case c == '"':
+			l.pos = i + 1
+			s := l.data[start:i]
+			if !utf8.Valid(s) {
+				return appendValidUTF8(nil, s)
+			}
+			return s
+		case c == '\\':
+			return l.unquote(start)
+		case c < 0x20:
+			l.pos = i
+			l.errorf("invalid character in string")
+			return nil
+		}
+	}
+	l.pos = len(l.data)
+	l.errorf("unterminated string")
+	return nil
+}
+
+// unquote reads the rest of a string containing escape sequences.
+func (l *Lexer) unquote(start int) []byte {
+	buf := make([]byte, 0, 2*(l.pos-start)+16)
+	i := start
+	for i < len(l.data) {
+		c := l.data[i]
+		switch {
+		case c == '"':
+			l.pos = i + 1
+			return buf
+
+		case c < 0x20:
+			l.pos = i
+			l.errorf("invalid character in string")
+			return nil
+
+		case c == '\\':
+			if i+1 >= len(l.data) {
+				i++
+				continue
+			}
+			switch e := l.data[i+1]; e {
+			case '"', '\\', '/':
+				buf = append(buf, e)
+			case 'b':
+				buf = append(buf, '\b')
+			case 'f':
+				buf = append(buf, '\f')
+			case 'n':
+				buf = append(buf, '\n')
+			case 'r':
+				buf = append(buf, '\r')
+			case 't':
+				buf = append(buf, '\t')
+			case 'u':
+				r, ok := l.hex4(i + 2)
+				if !ok {
+					return nil
+				}
+				i += 6
+				if utf16.IsSurrogate(r) {
+					r2, ok := rune(-1), false
+					if i+1 < len(l.data) && l.data[i] == '\\' && l.data[i+1] == 'u' {
+						r2, ok = l.hex4(i + 2)
+						if !ok {
+							return nil
+						}
+					}
+					if dec := utf16.DecodeRune(r, r2); dec != utf8.RuneError {
+						i += 6
+						r = dec
+					} else {
+						r = utf8.RuneError
+					}
+				}
+				buf = append(buf, string(r)...)
+				continue
+			default:
+				l.pos = i
+				l.errorf("invalid escape sequence")
+				return nil
+			}
+			i += 2
+
+		case c < utf8.RuneSelf:
+			buf = append(buf, c)
+			i++
+
+		default:
+			r, size := utf8.DecodeRune(l.data[i:])
+			buf = append(buf, string(r)...)
+			i += size
+		}
+	}
+	l.pos = len(l.data)
+	l.errorf("unterminated string")
+	return nil
+}
+
+func (l *Lexer) hex4(i int) (rune, bool) {
+	if i+4 > len(l.data) {
+		l.pos = i
+		l.errorf("invalid escape sequence")
+		return 0, false
+	}
+	v, err := strconv.ParseUint(string(l.data[i:i+4]), 16, 32)
+	if err != nil {
+		l.pos = i
+		l.errorf("invalid escape sequence")
+		return 0, false
+	}
+	return rune(v), true
+}
+
+func appendValidUTF8(buf, s []byte) []byte {
+	for len(s) > 0 {
+		r, size := utf8.DecodeRune(s)
+		buf = append(buf, string(r)...)
+		s = s[size:]
+	}
+	return buf
+}
+
+// Bool reads true or false.
+func (l *Lexer) Bool() bool {
+	switch l.peek() {
+	case 't':
+		return l.literal("true")
+	case 'f':
+		l.literal("false")
+		return false
+	}
+	if l.err == nil {
+		l.errorf("expected bool")
+	}
+	return false
+}
+
+// number returns the next number literal.
+func (l *Lexer) number() []byte {
+	c := l.peek()
+	if c != '-' && (c < '0' || c > '9') {
+		if l.err == nil {
+			l.errorf("expected number but got %q", c)
+		}
+		return nil
+	}
+	start := l.pos
+	for l.pos < len(l.data) && isNumberChar(l.data[l.pos]) {
+		l.pos++
+	}
+	if b := l.data[start:l.pos]; !validNumber(b) {
+		l.pos = start
+		l.errorf("invalid number %q", b)
+		return nil
+	}
+	return l.data[start:l.pos]
+}
+
+// validNumber reports whether b follows the grammar of JSON numbers, which rejects
+// leading zeros (e.g. "007") and misplaced signs (e.g. "1-2") like encoding/json does.
+func validNumber(b []byte) bool {
+	i := 0
+	if i < len(b) && b[i] == '-' {
+		i++
+	}
+	switch {
+	case i < len(b) && b[i] == '0':
+		i++
+	case i < len(b) && b[i] >= '1' && b[i] <= '9':
+		i = skipDigits(b, i)
+	default:
+		return false
+	}
+	if i < len(b) && b[i] == '.' {
+		if i++; i == len(b) || !isDigit(b[i]) {
+			return false
+		}
+		i = skipDigits(b, i)
+	}
+	if i < len(b) && (b[i] == 'e' || b[i] == 'E') {
+		i++
+		if i < len(b) && (b[i] == '+' || b[i] == '-') {
+			i++
+		}
+		if i == len(b) || !isDigit(b[i]) {
+			return false
+		}
+		i = skipDigits(b, i)
+	}
+	return i == len(b)
+}
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+
+// skipDigits returns the index of the first non-digit of b from i.
+func skipDigits(b []byte, i int) int {
+	for i < len(b) && isDigit(b[i]) {
+		i++
+	}
+	return i
+}
+
+func isNumberChar(c byte) bool {
+	return c >= '0' && c <= '9' || c == '-' || c == '+' || c == '.' || c == 'e' || c == 'E'
+}
+
+func (l *Lexer) parseInt(bits int) int64 {
+	start := l.pos
+	b := l.number()
+	if l.err != nil {
+		return 0
+	}
+	if v, ok := parseSmallInt(b); ok {
+		return v
+	}
+	v, err := strconv.ParseInt(string(b), 10, bits)
+	if err != nil {
+		l.pos = start
+		l.errorf("cannot read %s as int%d", b, bits)
+		return 0
+	}
+	return v
+}
+
+// parseSmallInt parses an integer of at most 9 digits, which fits in any int type.
+func parseSmallInt(b []byte) (int64, bool) {
+	neg := len(b) > 0 && b[0] == '-'
+	if neg {
+		b = b[1:]
+	}
+	if len(b) == 0 || len(b) > 9 || len(b) > 1 && b[0] == '0' {
+		return 0, false
+	}
+	var v int64
+	for _, c := range b {
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+		v = v*10 + int64(c-'0')
+	}
+	if neg {
+		v = -v
+	}
+	return v, true
+}
+
+func (l *Lexer) parseFloat(bits int) float64 {
+	start := l.pos
+	b := l.number()
+	if l.err != nil {
+		return 0
+	}
+	if v, ok := parseSmallInt(b); ok {
+		return float64(v)
+	}
+	v, err := strconv.ParseFloat(string(b), bits)
+	if err != nil {
+		l.pos = start
+		l.errorf("cannot read %s as float%d", b, bits)
+		return 0
+	}
+	return v
+}
+
+// Int reads an int.
+func (l *Lexer) Int() int { return int(l.parseInt(strconv.IntSize)) }
+
+// Int32 reads an int32.
+func (l *Lexer) Int32() int32 { return int32(l.parseInt(32)) }
+
+// Int64 reads an int64.
+func (l *Lexer) Int64() int64 { return l.parseInt(64) }
+
+// Float32 reads a float32.
+func (l *Lexer) Float32() float32 { return float32(l.parseFloat(32)) }
+
+// Float64 reads a float64.
+func (l *Lexer) Float64() float64 { return l.parseFloat(64) }
+
+// Skip skips the next value.
+func (l *Lexer) Skip() {
+	switch l.peek() {
+	case '{':
+		l.Delim('{')
+		for l.More() {
+			l.Key()
+			l.Skip()
+		}
+		l.Delim('}')
+	case '[':
+		l.Delim('[')
+		for l.More() {
+			l.Skip()
+		}
+		l.Delim(']')
+	case '"':
+		l.bytes()
+	case 't', 'f':
+		l.Bool()
+	case 'n':
+		l.Null()
+	default:
+		l.parseFloat(64)
+	}
+}
+
+// Raw skips the next value and returns it.
+func (l *Lexer) Raw() []byte {
+	l.skipSpace()
+	start := l.pos
+	l.Skip()
+	if l.err != nil {
+		return nil
+	}
+	return l.data[start:l.pos]
+}
+
+// Unmarshal reads the next value into v with encoding/json.
+// It is used for types without generated methods.
+func (l *Lexer) Unmarshal(v interface{}) {
+	if data := l.Raw(); l.err == nil {
+		l.AddError(json.Unmarshal(data, v))
+	}
+}
+
+// Writer builds a JSON value.
+type Writer struct {
+	buf []byte
+	err error
+}
+
+// bufPool keeps buffers of Writers, so that large values don't grow a buffer from scratch.
+var bufPool = sync.Pool{
+	New: func() interface{} { return make([]byte, 0, 1024) },
+}
+
+// NewWriter creates a writer. Bytes must be called to release its buffer.
+func NewWriter() *Writer {
+	return &Writer{buf: bufPool.Get().([]byte)[:0]}
+}
+
+// Bytes returns a copy of the written value, or the first error.
+func (w *Writer) Bytes() ([]byte, error) {
+	buf := w.buf
+	w.buf = nil
+	defer bufPool.Put(buf)
+
+	if w.err != nil {
+		return nil, w.err
+	}
+	return append([]byte(nil), buf...), nil
+}
+
+// Raw writes s as is.
+func (w *Writer) Raw(s string) {
+	w.buf = append(w.buf, s...)
+}
+
+// Bool writes a bool.
+func (w *Writer) Bool(v bool) {
+	w.buf = strconv.AppendBool(w.buf, v)
+}
+
+// Int writes an int.
+func (w *Writer) Int(v int) {
+	w.buf = strconv.AppendInt(w.buf, int64(v), 10)
+}
+
+// Int32 writes an int32.
+func (w *Writer) Int32(v int32) {
+	w.buf = strconv.AppendInt(w.buf, int64(v), 10)
+}
+
+// Int64 writes an int64.
+func (w *Writer) Int64(v int64) {
+	w.buf = strconv.AppendInt(w.buf, v, 10)
+}
+
+// Float32 writes a float32.
+func (w *Writer) Float32(v float32) {
+	w.float(float64(v), 32)
+}
+
+// Float64 writes a float64.
+func (w *Writer) Float64(v float64) {
+	w.float(v, 64)
+}
+
+// float formats like encoding/json does.
+func (w *Writer) float(f float64, bits int) {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		if w.err == nil {
+			w.err = &json.UnsupportedValueError{Str: strconv.FormatFloat(f, 'g', -1, bits)}
+		}
+		w.buf = append(w.buf, '0')
+		return
+	}
+
+	abs := math.Abs(f)
+	format := byte('f')
+	if abs != 0 {
+		if bits == 64 && (abs < 1e-6 || abs >= 1e21) || bits == 32 && (float32(abs) < 1e-6 || float32(abs) >= 1e21) {
+			format = 'e'
+		}
+	}
+	w.buf = strconv.AppendFloat(w.buf, f, format, -1, bits)
+	if format == 'e' {
+		// clean up e-09 to e-9
+		n := len(w.buf)
+		if n >= 4 && w.buf[n-4] == 'e' && w.buf[n-3] == '-' && w.buf[n-2] == '0' {
+			w.buf[n-2] = w.buf[n-1]
+			w.buf = w.buf[:n-1]
+		}
+	}
+}
+
+const hex = "0123456789abcdef"
+
+// String writes a quoted string, escaping html characters like encoding/json does.
+func (w *Writer) String(s string) {
+	w.buf = append(w.buf, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		if c := s[i]; c < utf8.RuneSelf {
+			if c >= 0x20 && c != '"' && c != '\\' && c != '<' && c != '>' && c != '&' {
+				i++
+				continue
+			}
+			w.buf = append(w.buf, s[start:i]...)
+			switch c {
+			case '"', '\\':
+				w.buf = append(w.buf, '\\', c)
+			case '\n':
+				w.buf = append(w.buf, '\\', 'n')
+			case '\r':
+				w.buf = append(w.buf, '\\', 'r')
+			case '\t':
+				w.buf = append(w.buf, '\\', 't')
+			default:
+				w.buf = append(w.buf, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			w.buf = append(w.buf, s[start:i]...)
+			w.buf = append(w.buf, `\ufffd`...)
+			i += size
+			start = i
+			continue
+		}
+		if r == '\u2028' || r == '\u2029' {
+			w.buf = append(w.buf, s[start:i]...)
+			w.buf = append(w.buf, '\\', 'u', '2', '0', '2', hex[r&0xF])
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	w.buf = append(w.buf, s[start:]...)
+	w.buf = append(w.buf, '"')
+}
+
+// Marshal writes v with encoding/json.
+// It is used for types without generated methods.
+func (w *Writer) Marshal(v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		if w.err == nil {
+			w.err = err
+		}
+		data = []byte("null")
+	}
+	w.buf = append(w.buf, data...)
+}
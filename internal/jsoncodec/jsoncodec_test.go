@@ -0,0 +1,124 @@
+package jsoncodec
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// lexerCase reads in with read, which returns want, or fails with a SyntaxError if want is nil.
+type lexerCase struct {
+	in   string
+	read func(*Lexer) interface{}
+	want interface{}
+}
+
+func TestLexer(t *testing.T) {
+	str := func(l *Lexer) interface{} { return l.String() }
+	i32 := func(l *Lexer) interface{} { return l.Int32() }
+	i64 := func(l *Lexer) interface{} { return l.Int64() }
+	f32 := func(l *Lexer) interface{} { return l.Float32() }
+	f64 := func(l *Lexer) interface{} { return l.Float64() }
+	raw := func(l *Lexer) interface{} { return string(l.Raw()) }
+
+	for _, group := range []struct {
+		name  string
+		cases []lexerCase
+	}{
+		{"Truncated input", []lexerCase{
+			{``, raw, nil},
+			{`{"a": 1`, raw, nil},
+			{`[1, 2`, raw, nil},
+			{`{"a"`, raw, nil},
+			{`"abc`, str, nil},
+			{`"abc\`, str, nil},
+			{`tru`, raw, nil},
+			{`-`, i64, nil},
+		}},
+		{"Escapes", []lexerCase{
+			{`"a\"\\\/\b\f\n\r\t"`, str, "a\"\\/\b\f\n\r\t"},
+			{`"éA"`, str, "éA"},
+			{`"\x"`, str, nil},
+			{`"\u12"`, str, nil},
+			{`"\u12G4"`, str, nil},
+			{"\"a\nb\"", str, nil},
+		}},
+		{"Surrogate pairs", []lexerCase{
+			{`"😀"`, str, "😀"},
+			{`"\ud83d"`, str, "�"},
+			{`"\ud83dx"`, str, "�x"},
+			{`"\ude00\ud83d"`, str, "��"},
+			{`"\ud83dA"`, str, "�A"},
+			{`"\ud83d\u12"`, str, nil},
+		}},
+		{"Integer overflow", []lexerCase{
+			{`2147483647`, i32, int32(2147483647)},
+			{`-2147483648`, i32, int32(-2147483648)},
+			{`2147483648`, i32, nil},
+			{`-2147483649`, i32, nil},
+			{`9223372036854775807`, i64, int64(9223372036854775807)},
+			{`-9223372036854775808`, i64, int64(-9223372036854775808)},
+			{`9223372036854775808`, i64, nil},
+			{`1.5`, i64, nil},
+			{`1e3`, i64, nil},
+		}},
+		{"Number grammar", []lexerCase{
+			{`-0`, i64, int64(0)},
+			{`0.5`, f64, float64(0.5)},
+			{`007`, i64, nil},
+			{`-01`, i64, nil},
+			{`01.5`, f64, nil},
+			{`1-2`, i64, nil},
+			{`1e-2-3`, f64, nil},
+			{`+1`, i64, nil},
+			{`.5`, f64, nil},
+			{`1.`, f64, nil},
+			{`1.e3`, f64, nil},
+			{`1e+`, f64, nil},
+			{`--1`, i64, nil},
+			{`[1, 007]`, raw, nil},
+		}},
+		{"Exponent floats", []lexerCase{
+			{`1e3`, f64, float64(1000)},
+			{`1.5E+2`, f64, float64(150)},
+			{`-2.5e-3`, f64, float64(-0.0025)},
+			{`1e400`, f64, nil},
+			{`3.4e39`, f32, nil},
+			{`1.5e-1`, f32, float32(0.15)},
+			{`1e`, f64, nil},
+		}},
+		{"Skipping unknown nested values", []lexerCase{
+			{` {"a": [1, {"b": null}, "c\"]"], "d": {}, "e": [[], [true, false]]} `, raw,
+				`{"a": [1, {"b": null}, "c\"]"], "d": {}, "e": [[], [true, false]]}`},
+			{`[1e3, -0.5, "😀"]`, raw, `[1e3, -0.5, "😀"]`},
+			{`{"a": [1, 2}`, raw, nil},
+			{`{"a": {"b": 1,}}`, raw, nil},
+			{`[1] 2`, raw, nil},
+		}},
+	} {
+		Convey(group.name, t, func() {
+			for _, c := range group.cases {
+				l := NewLexer([]byte(c.in))
+				got := c.read(l)
+				err := l.Done()
+				if c.want == nil {
+					So(err, ShouldNotBeNil)
+					So(err, ShouldHaveSameTypeAs, &SyntaxError{})
+				} else {
+					So(err, ShouldBeNil)
+					So(got, ShouldEqual, c.want)
+				}
+			}
+		})
+	}
+}
+
+func TestFoldKey(t *testing.T) {
+	Convey("FoldKey matches keys case-insensitively", t, func() {
+		keys := []string{"id", "summonerLevel"}
+		So(FoldKey([]byte("ID"), keys), ShouldEqual, "id")
+		So(FoldKey([]byte("SummonerLEVEL"), keys), ShouldEqual, "summonerLevel")
+		So(FoldKey([]byte("summoner"), keys), ShouldEqual, "")
+		So(FoldKey(nil, keys), ShouldEqual, "")
+	})
+}
@@ -0,0 +1,134 @@
+package uritemplates
+
+import (
+	"bytes"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Match is the reverse of Expand. It returns values of variables, if path is
+// an expansion of the template. Variables which are not expanded (e.g. empty
+// values) are not in the map.
+//
+// path must be escaped as Expand does, e.g. URL.EscapedPath() of a request.
+// Values truncated by prefix modifiers are returned as they are.
+func (t *URITemplate) Match(path string) (map[string]string, bool) {
+	t.matcherOnce.Do(t.compileMatcher)
+
+	m := t.matcher.FindStringSubmatch(path)
+	if m == nil {
+		return nil, false
+	}
+	values := make(map[string]string)
+	i := 1
+	for _, p := range t.parts {
+		if len(p.terms) == 0 {
+			continue
+		}
+		if !p.match(m[i], values) {
+			return nil, false
+		}
+		i++
+	}
+	return values, true
+}
+
+// LiteralLen returns the length of literal parts of the template.
+// If several templates match a path, the one with the longest literal is usually the most specific.
+func (t *URITemplate) LiteralLen() int {
+	n := 0
+	for _, p := range t.parts {
+		if len(p.terms) == 0 {
+			n += len(p.raw)
+		}
+	}
+	return n
+}
+
+func (t *URITemplate) compileMatcher() {
+	var buf bytes.Buffer
+	buf.WriteString("^")
+	for _, p := range t.parts {
+		if len(p.terms) == 0 {
+			buf.WriteString(regexp.QuoteMeta(p.raw))
+			continue
+		}
+		buf.WriteString("(")
+		buf.WriteString(p.valuePattern())
+		buf.WriteString(")")
+	}
+	buf.WriteString("$")
+	t.matcher = regexp.MustCompile(buf.String())
+}
+
+// valuePattern returns a regexp matching an expansion of the expression.
+func (tp *templatePart) valuePattern() string {
+	switch {
+	case tp.allowReserved: // "+" and "#"
+		return ".*?"
+	case tp.first == "/":
+		return "(?:/[^/?#]*)*"
+	case tp.first == "?" || tp.first == "&":
+		return "[^#]*?"
+	default: // "", "." and ";", whose values never contain "/", "?" and "#".
+		return "[^/?#]*"
+	}
+}
+
+// match sets values of terms from s, which is an expansion of the expression.
+func (tp *templatePart) match(s string, values map[string]string) bool {
+	if s == "" {
+		return true // no value is expanded.
+	}
+	if !strings.HasPrefix(s, tp.first) {
+		return false
+	}
+	s = s[len(tp.first):]
+
+	// commas in values are escaped unless reserved characters are allowed,
+	// so a single term takes everything.
+	vals := []string{s}
+	if len(tp.terms) > 1 {
+		vals = strings.Split(s, tp.sep)
+	}
+
+	if tp.named {
+		for _, v := range vals {
+			name, value := v, ""
+			if i := strings.Index(v, "="); i != -1 {
+				name, value = v[:i], v[i+1:]
+			}
+			if !tp.hasTerm(name) {
+				return false
+			}
+			unescaped, err := url.PathUnescape(value)
+			if err != nil {
+				return false
+			}
+			values[name] = unescaped
+		}
+		return true
+	}
+
+	if len(vals) > len(tp.terms) {
+		return false
+	}
+	for i, v := range vals {
+		unescaped, err := url.PathUnescape(v)
+		if err != nil {
+			return false
+		}
+		values[tp.terms[i].name] = unescaped
+	}
+	return true
+}
+
+func (tp *templatePart) hasTerm(name string) bool {
+	for _, term := range tp.terms {
+		if term.name == name {
+			return true
+		}
+	}
+	return false
+}
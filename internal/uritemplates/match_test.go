@@ -0,0 +1,46 @@
+package uritemplates
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMatch(t *testing.T) {
+	Convey("Match is the reverse of Expand", t, func() {
+		for _, data := range []struct {
+			template string
+			values   map[string]string
+		}{
+			{"/api/lol/{region}/v1.4/summoner/{summonerIds}", map[string]string{"region": "na", "summonerIds": "1,2"}},
+			{"/static{/dir,file}", map[string]string{"dir": "img", "file": "a b.png"}},
+			{"/search{?q,lang}", map[string]string{"q": "a&b", "lang": "ko"}},
+			{"/files/{+path}", map[string]string{"path": "a/b,c"}},
+			{"/x{.ext}", map[string]string{"ext": "json"}},
+		} {
+			tpl := MustParse(data.template)
+			expanded, err := tpl.Expand(data.values)
+			So(err, ShouldBeNil)
+
+			values, ok := tpl.Match(expanded)
+			So(ok, ShouldBeTrue)
+			So(values, ShouldResemble, data.values)
+		}
+	})
+
+	Convey("Match rejects other paths", t, func() {
+		tpl := MustParse("/api/lol/{region}/v1.4/summoner/{summonerIds}")
+		for _, path := range []string{
+			"/api/lol/na/v1.4/summoner/1/name",
+			"/api/lol/na/v1.3/summoner/1",
+			"/api/lol/na/v1.4/summoner/%zz",
+		} {
+			_, ok := tpl.Match(path)
+			So(ok, ShouldBeFalse)
+		}
+	})
+
+	Convey("MustParse panics on malformed templates", t, func() {
+		So(func() { MustParse("/{a") }, ShouldPanic)
+	})
+}
@@ -23,6 +23,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 )
 
 var (
@@ -56,6 +57,9 @@ func escape(s string, allowReserved bool) (escaped string) {
 type URITemplate struct {
 	raw   string
 	parts []templatePart
+
+	matcherOnce sync.Once
+	matcher     *regexp.Regexp // built by Match
 }
 
 // Parse parses a URI template string into a UriTemplate object.
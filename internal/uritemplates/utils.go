@@ -1,5 +1,7 @@
 package uritemplates
 
+import "strconv"
+
 func Expand(path string, values map[string]string) (string, error) {
 	template, err := Parse(path)
 	if err != nil {
@@ -7,3 +9,13 @@ func Expand(path string, values map[string]string) (string, error) {
 	}
 	return template.Expand(values)
 }
+
+// MustParse is like Parse but panics if rawtemplate cannot be parsed.
+// It simplifies initialization of package-level templates.
+func MustParse(rawtemplate string) *URITemplate {
+	t, err := Parse(rawtemplate)
+	if err != nil {
+		panic("uritemplates: Parse(" + strconv.Quote(rawtemplate) + "): " + err.Error())
+	}
+	return t
+}
@@ -1,25 +1,48 @@
 // Package lol provides a client for league legends rest api.
 package lol
 
-//go:generate go run go-lol-generator/main.go
+//go:generate go run ./go-lol-generator -codec lol.codec.generated.go -fixtures fixtures.generated_test.go
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"log"
 	"net/http"
 	"net/url"
+	"strconv"
+	"time"
 
 	"golang.org/x/net/context"
 	"golang.org/x/net/context/ctxhttp"
 )
 
 var (
-	// ErrNotSupportedRegion is returned if operation is not supported in a region.
+	// ErrNotSupportedRegion is matched by *UnsupportedRegionError, which calls return, with errors.Is.
+	// Comparing errors of calls with == does not match it.
 	ErrNotSupportedRegion = errors.New("go-lol: operation does not work for such region")
+	// ErrDryRun is returned by calls of a client created WithDryRun, instead of sending requests.
+	ErrDryRun = errors.New("go-lol: dry run")
 )
 
+// UnsupportedRegionError is returned by Do of a call if the operation is not supported in the region.
+// See SupportedRegions to check it before calling.
+type UnsupportedRegionError struct {
+	Operation string // name of the operation (e.g. "Summoners")
+	Region    Region
+}
+
+func (e *UnsupportedRegionError) Error() string {
+	return fmt.Sprintf("go-lol: %s does not work for region %s", e.Operation, e.Region)
+}
+
+// Is reports whether target is ErrNotSupportedRegion.
+func (e *UnsupportedRegionError) Is(target error) bool {
+	return target == ErrNotSupportedRegion
+}
+
 // ClientFactory is used to get a http client.
 // This must NOT return nil.
 type ClientFactory func(context.Context) *http.Client
@@ -31,35 +54,279 @@ type Client struct {
 }
 
 type StaticClient struct {
-	getClient ClientFactory
+	getClient  ClientFactory
+	hostScheme HostScheme
+	dryRun     func(format string, args ...interface{}) // nil unless WithDryRun
+}
+
+// HostScheme selects hosts which requests are sent to.
+type HostScheme int
+
+const (
+	// LegacyHosts sends requests to hosts of regions, e.g. na.api.pvp.net. It's the default.
+	LegacyHosts HostScheme = iota
+	// PlatformHosts sends requests to hosts of platforms (e.g. na1.api.riotgames.com) or
+	// continental clusters (e.g. americas.api.riotgames.com), following Operation.Routing.
+	PlatformHosts
+)
+
+// ClientOption configures a client. See New and NewStatic.
+type ClientOption func(*StaticClient)
+
+// WithHostScheme selects hosts which requests are sent to. The default is LegacyHosts.
+func WithHostScheme(scheme HostScheme) ClientOption {
+	return func(c *StaticClient) {
+		c.hostScheme = scheme
+	}
+}
+
+// WithDryRun makes calls log requests with logf (log.Printf if nil) and return ErrDryRun,
+// without sending them. Api keys in logged urls are redacted.
+func WithDryRun(logf func(format string, args ...interface{})) ClientOption {
+	if logf == nil {
+		logf = log.Printf
+	}
+	return func(c *StaticClient) {
+		c.dryRun = logf
+	}
 }
 
 // New creates a new league of legends client.
-func New(clientFactory ClientFactory, key string) *Client {
+func New(clientFactory ClientFactory, key string, opts ...ClientOption) *Client {
 	return &Client{
-		StaticClient: NewStatic(clientFactory),
+		StaticClient: NewStatic(clientFactory, opts...),
 		apiKey:       key,
 	}
 }
 
-func NewStatic(clientFactory ClientFactory) StaticClient {
+func NewStatic(clientFactory ClientFactory, opts ...ClientOption) StaticClient {
 	if clientFactory == nil {
 		clientFactory = DefaultClientFactory
 	}
 
-	return StaticClient{
+	c := StaticClient{
 		getClient: clientFactory,
 	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// baseURL returns scheme and host of a request to region, routed by routing.
+// fixed is the base url of the operation with LegacyHosts, or empty if it's the host of region.
+func (c StaticClient) baseURL(region Region, routing Routing, fixed string) string {
+	if c.hostScheme == PlatformHosts {
+		switch routing {
+		case PlatformRouting:
+			return "https://" + region.PlatformHost()
+		case ClusterRouting:
+			return "https://" + region.Cluster().Host()
+		}
+	}
+	if fixed != "" {
+		return fixed
+	}
+	return region.baseURL()
+}
+
+// CallOption configures a call. See Options of calls.
+type CallOption func(*callOptions)
+
+// callOptions is set by CallOption, and used by StaticClient.doRequest.
+type callOptions struct {
+	timeout    time.Duration
+	header     http.Header
+	httpClient *http.Client
+	retry      RetryPolicy
+	priority   Priority
+}
+
+// RetryPolicy retries requests failed with status 429 or 5xx, or without a response.
+// Only GET and HEAD requests are retried, unless NonIdempotent is set.
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the first attempt.
+	MaxRetries int
+	// Backoff is the wait before the first retry, doubled on each retry.
+	// Retry-After of the response is used instead, if it's longer.
+	// DefaultBackoff is used if it's zero.
+	Backoff time.Duration
+	// NonIdempotent retries requests of other methods (e.g. POST creating tournament codes),
+	// which may be applied twice if the failed attempt reached the server.
+	NonIdempotent bool
+}
+
+// DefaultBackoff is the wait before the first retry of a RetryPolicy without Backoff.
+const DefaultBackoff = 500 * time.Millisecond
+
+// Priority of a call. Clients returned by a ClientFactory (e.g. a rate limiter)
+// can read it from the context with PriorityFromContext.
+type Priority int
+
+const (
+	LowPriority    Priority = -1
+	NormalPriority Priority = 0
+	HighPriority   Priority = 1
+)
+
+type priorityCtxKeyType struct{}
+
+var priorityCtxKey priorityCtxKeyType
+
+// PriorityFromContext returns the priority of the call sending a request with ctx.
+// It's passed to ClientFactory, and is the context of the request.
+func PriorityFromContext(ctx context.Context) Priority {
+	p, _ := ctx.Value(priorityCtxKey).(Priority)
+	return p
+}
+
+// WithTimeout limits time of each attempt of the call, including reading the response.
+func WithTimeout(d time.Duration) CallOption {
+	return func(o *callOptions) {
+		o.timeout = d
+	}
 }
 
-func (c StaticClient) doRequest(ctx context.Context, method, urlStr string, body io.Reader) (*http.Response, error) {
-	req, err := http.NewRequest(method, urlStr, body)
+// WithHeader adds a header to requests of the call.
+func WithHeader(key, value string) CallOption {
+	return func(o *callOptions) {
+		if o.header == nil {
+			o.header = make(http.Header)
+		}
+		o.header.Add(key, value)
+	}
+}
+
+// WithHTTPClient sends requests of the call with client, instead of the one of ClientFactory.
+func WithHTTPClient(client *http.Client) CallOption {
+	return func(o *callOptions) {
+		o.httpClient = client
+	}
+}
+
+// WithoutCache asks caching transports and proxies not to answer from their caches.
+func WithoutCache() CallOption {
+	return WithHeader("Cache-Control", "no-cache")
+}
+
+// WithRetry retries failed requests of the call. See RetryPolicy.
+func WithRetry(policy RetryPolicy) CallOption {
+	return func(o *callOptions) {
+		o.retry = policy
+	}
+}
+
+// WithPriority sets priority of the call. See Priority.
+func WithPriority(p Priority) CallOption {
+	return func(o *callOptions) {
+		o.priority = p
+	}
+}
+
+func (c StaticClient) doRequest(ctx context.Context, method, urlStr string, body io.Reader, opts *callOptions) (*http.Response, error) {
+	// body is read once, to be sent again on retries.
+	var data []byte
+	if body != nil {
+		var err error
+		if data, err = ioutil.ReadAll(body); err != nil {
+			return nil, err
+		}
+	}
+	if c.dryRun != nil {
+		if body != nil {
+			c.dryRun("go-lol: dry run: %s %s %s", method, redactKey(urlStr), data)
+		} else {
+			c.dryRun("go-lol: dry run: %s %s", method, redactKey(urlStr))
+		}
+		return nil, ErrDryRun
+	}
+	if opts.priority != NormalPriority {
+		ctx = context.WithValue(ctx, priorityCtxKey, opts.priority)
+	}
+
+	httpClient := opts.httpClient
+	if httpClient == nil {
+		httpClient = c.getClient(ctx)
+	}
+	if opts.timeout > 0 {
+		withTimeout := *httpClient
+		withTimeout.Timeout = opts.timeout
+		httpClient = &withTimeout
+	}
+
+	backoff := opts.retry.Backoff
+	if backoff <= 0 {
+		backoff = DefaultBackoff
+	}
+	retryable := method == "GET" || method == "HEAD" || opts.retry.NonIdempotent
+	for retries := 0; ; retries++ {
+		var reqBody io.Reader
+		if body != nil {
+			reqBody = bytes.NewReader(data)
+		}
+		req, err := http.NewRequest(method, urlStr, reqBody)
+		if err != nil {
+			return nil, err
+		}
+		for key, values := range opts.header {
+			req.Header[key] = append(req.Header[key], values...)
+		}
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+
+		res, err := ctxhttp.Do(ctx, httpClient, req)
+		if !retryable || retries >= opts.retry.MaxRetries || !shouldRetry(ctx, res, err) {
+			return res, err
+		}
+
+		wait := retryAfter(res, backoff)
+		closeBody(res)
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		backoff *= 2
+	}
+}
+
+// redactKey returns urlStr with the value of api_key replaced, so that it can be logged.
+func redactKey(urlStr string) string {
+	u, err := url.Parse(urlStr)
+	if err != nil {
+		return urlStr
+	}
+	q := u.Query()
+	if q.Get("api_key") == "" {
+		return urlStr
+	}
+	q.Set("api_key", "REDACTED")
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// shouldRetry returns true if a request failed with status 429 or 5xx, or without a response.
+func shouldRetry(ctx context.Context, res *http.Response, err error) bool {
 	if err != nil {
-		return nil, err
+		return ctx.Err() == nil
 	}
+	return res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= 500
+}
 
-	httpClient := c.getClient(ctx)
-	return ctxhttp.Do(ctx, httpClient, req)
+// retryAfter returns Retry-After of res if it's longer than backoff.
+func retryAfter(res *http.Response, backoff time.Duration) time.Duration {
+	if res == nil {
+		return backoff
+	}
+	sec, err := strconv.Atoi(res.Header.Get("Retry-After"))
+	if err != nil {
+		return backoff
+	}
+	if d := time.Duration(sec) * time.Second; d > backoff {
+		return d
+	}
+	return backoff
 }
 
 // HTTPError represents an error returned from riot api server.
@@ -0,0 +1,44 @@
+// Package loltest provides fake riot api servers to test code using go-lol offline.
+package loltest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	lol "github.com/kdy1997/go-lol"
+	"golang.org/x/net/context"
+)
+
+// Transport is a http.RoundTripper which serves every request with Handler
+// instead of sending it over the network.
+type Transport struct {
+	Handler http.Handler
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rec := httptest.NewRecorder()
+	t.Handler.ServeHTTP(rec, req)
+
+	res := rec.Result()
+	res.Request = req
+	return res, nil
+}
+
+// ClientFactory returns a lol.ClientFactory whose clients are served by h.
+func ClientFactory(h http.Handler) lol.ClientFactory {
+	client := &http.Client{Transport: Transport{Handler: h}}
+	return func(context.Context) *http.Client {
+		return client
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, code int) {
+	http.Error(w, http.StatusText(code), code)
+}
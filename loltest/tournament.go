@@ -0,0 +1,238 @@
+package loltest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	lol "github.com/kdy1997/go-lol"
+)
+
+const tournamentPathPrefix = "/tournament/public/v1"
+
+// TournamentProvider is a fake of tournament-provider api.
+// Providers, tournaments, codes and lobby events are kept in memory.
+//
+// Requests without api_key are rejected with 401.
+type TournamentProvider struct {
+	mu sync.Mutex
+
+	lastID      int32
+	providers   map[int32]*lol.ProviderRegistrationParameters
+	tournaments map[int32]*lol.TournamentRegistrationParameters
+	codes       map[string]*lol.TournamentCode
+	events      map[string][]*lol.LobbyEvent
+}
+
+// NewTournamentProvider creates an empty fake tournament-provider api.
+func NewTournamentProvider() *TournamentProvider {
+	return &TournamentProvider{
+		providers:   make(map[int32]*lol.ProviderRegistrationParameters),
+		tournaments: make(map[int32]*lol.TournamentRegistrationParameters),
+		codes:       make(map[string]*lol.TournamentCode),
+		events:      make(map[string][]*lol.LobbyEvent),
+	}
+}
+
+// AddLobbyEvent records a lobby event for tournament code, as the game server does.
+// It returns false if code does not exist.
+func (tp *TournamentProvider) AddLobbyEvent(code string, ev *lol.LobbyEvent) bool {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+
+	if _, ok := tp.codes[code]; !ok {
+		return false
+	}
+	tp.events[code] = append(tp.events[code], ev)
+	return true
+}
+
+// ServeHTTP implements http.Handler.
+func (tp *TournamentProvider) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("api_key") == "" {
+		writeError(w, http.StatusUnauthorized)
+		return
+	}
+	if !strings.HasPrefix(r.URL.Path, tournamentPathPrefix) {
+		writeError(w, http.StatusNotFound)
+		return
+	}
+	path := r.URL.Path[len(tournamentPathPrefix):]
+
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+
+	switch {
+	case r.Method == "POST" && path == "/provider":
+		tp.createProvider(w, r)
+	case r.Method == "POST" && path == "/tournament":
+		tp.createTournament(w, r)
+	case r.Method == "POST" && path == "/code":
+		tp.createCodes(w, r)
+	case r.Method == "GET" && strings.HasPrefix(path, "/code/"):
+		tp.getCode(w, path[len("/code/"):])
+	case r.Method == "PUT" && strings.HasPrefix(path, "/code/"):
+		tp.updateCode(w, r, path[len("/code/"):])
+	case r.Method == "GET" && strings.HasPrefix(path, "/lobby/events/by-code/"):
+		tp.lobbyEvents(w, path[len("/lobby/events/by-code/"):])
+	default:
+		writeError(w, http.StatusNotFound)
+	}
+}
+
+func (tp *TournamentProvider) nextID() int32 {
+	tp.lastID++
+	return tp.lastID
+}
+
+func (tp *TournamentProvider) createProvider(w http.ResponseWriter, r *http.Request) {
+	params := &lol.ProviderRegistrationParameters{}
+	if err := json.NewDecoder(r.Body).Decode(params); err != nil {
+		writeError(w, http.StatusBadRequest)
+		return
+	}
+	if _, err := lol.RegionByName(strings.ToLower(params.Region)); err != nil || params.URL == "" {
+		writeError(w, http.StatusBadRequest)
+		return
+	}
+
+	id := tp.nextID()
+	tp.providers[id] = params
+	writeJSON(w, id)
+}
+
+func (tp *TournamentProvider) createTournament(w http.ResponseWriter, r *http.Request) {
+	params := &lol.TournamentRegistrationParameters{}
+	if err := json.NewDecoder(r.Body).Decode(params); err != nil {
+		writeError(w, http.StatusBadRequest)
+		return
+	}
+	if _, ok := tp.providers[params.ProviderID]; !ok {
+		writeError(w, http.StatusBadRequest)
+		return
+	}
+
+	id := tp.nextID()
+	tp.tournaments[id] = params
+	writeJSON(w, id)
+}
+
+func (tp *TournamentProvider) createCodes(w http.ResponseWriter, r *http.Request) {
+	tournamentID, err := strconv.ParseInt(r.URL.Query().Get("tournamentId"), 10, 32)
+	if err != nil {
+		writeError(w, http.StatusBadRequest)
+		return
+	}
+	tournament, ok := tp.tournaments[int32(tournamentID)]
+	if !ok {
+		writeError(w, http.StatusNotFound)
+		return
+	}
+
+	count := int64(1)
+	if s := r.URL.Query().Get("count"); s != "" {
+		count, err = strconv.ParseInt(s, 10, 32)
+		if err != nil || count < 1 || count > 1000 {
+			writeError(w, http.StatusBadRequest)
+			return
+		}
+	}
+
+	params := &lol.TournamentCodeParameters{}
+	if err := json.NewDecoder(r.Body).Decode(params); err != nil {
+		writeError(w, http.StatusBadRequest)
+		return
+	}
+	if params.TeamSize < 1 || params.TeamSize > 5 {
+		writeError(w, http.StatusBadRequest)
+		return
+	}
+
+	provider := tp.providers[tournament.ProviderID]
+	codes := make([]string, 0, count)
+	for i := int64(0); i < count; i++ {
+		id := tp.nextID()
+		code := &lol.TournamentCode{
+			Code:         fmt.Sprintf("%s%04d-%d", strings.ToUpper(provider.Region), tournamentID, id),
+			ID:           id,
+			Map:          params.MapType,
+			MetaData:     params.Metadata,
+			PickType:     params.PickType,
+			ProviderID:   tournament.ProviderID,
+			Region:       strings.ToUpper(provider.Region),
+			Spectators:   params.SpectatorType,
+			TeamSize:     params.TeamSize,
+			TournamentID: int32(tournamentID),
+		}
+		code.LobbyName = code.Code
+		code.Password = strconv.Itoa(int(id))
+		if params.AllowedSummonerIds != nil {
+			code.Participants = params.AllowedSummonerIds.Participants
+		}
+
+		tp.codes[code.Code] = code
+		codes = append(codes, code.Code)
+	}
+	writeJSON(w, codes)
+}
+
+func (tp *TournamentProvider) getCode(w http.ResponseWriter, code string) {
+	tc, ok := tp.codes[code]
+	if !ok {
+		writeError(w, http.StatusNotFound)
+		return
+	}
+	writeJSON(w, tc)
+}
+
+func (tp *TournamentProvider) updateCode(w http.ResponseWriter, r *http.Request, code string) {
+	tc, ok := tp.codes[code]
+	if !ok {
+		writeError(w, http.StatusNotFound)
+		return
+	}
+
+	params := &lol.TournamentCodeUpdateParameters{}
+	if err := json.NewDecoder(r.Body).Decode(params); err != nil {
+		writeError(w, http.StatusBadRequest)
+		return
+	}
+
+	if params.AllowedParticipants != "" {
+		var participants []int64
+		for _, s := range strings.Split(params.AllowedParticipants, ",") {
+			id, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+			if err != nil {
+				writeError(w, http.StatusBadRequest)
+				return
+			}
+			participants = append(participants, id)
+		}
+		tc.Participants = participants
+	}
+	if params.MapType != "" {
+		tc.Map = params.MapType
+	}
+	if params.PickType != "" {
+		tc.PickType = params.PickType
+	}
+	if params.SpectatorType != "" {
+		tc.Spectators = params.SpectatorType
+	}
+}
+
+func (tp *TournamentProvider) lobbyEvents(w http.ResponseWriter, code string) {
+	if _, ok := tp.codes[code]; !ok {
+		writeError(w, http.StatusNotFound)
+		return
+	}
+
+	events := tp.events[code]
+	if events == nil {
+		events = make([]*lol.LobbyEvent, 0)
+	}
+	writeJSON(w, &lol.LobbyEvents{EventList: events})
+}
@@ -0,0 +1,83 @@
+package loltest_test
+
+import (
+	"testing"
+
+	lol "github.com/kdy1997/go-lol"
+	"github.com/kdy1997/go-lol/loltest"
+	. "github.com/smartystreets/goconvey/convey"
+	"golang.org/x/net/context"
+)
+
+func TestTournamentProvider(t *testing.T) {
+	Convey("TournamentProvider", t, func() {
+		fake := loltest.NewTournamentProvider()
+		client := lol.New(loltest.ClientFactory(fake), "test-key")
+		ctx := context.TODO()
+
+		providerID, err := client.CreateTournamentProvider(ctx).Body(&lol.ProviderRegistrationParameters{
+			Region: "NA",
+			URL:    "https://example.com/callback",
+		}).Do()
+		So(err, ShouldBeNil)
+
+		tournamentID, err := client.CreateTournament(ctx).Body(&lol.TournamentRegistrationParameters{
+			Name:       "test",
+			ProviderID: providerID,
+		}).Do()
+		So(err, ShouldBeNil)
+
+		codes, err := client.CreateTournamentCodes(ctx).TournamentId(tournamentID).Count(2).Body(&lol.TournamentCodeParameters{
+			MapType:       "SUMMONERS_RIFT",
+			PickType:      "TOURNAMENT_DRAFT",
+			SpectatorType: "ALL",
+			TeamSize:      5,
+		}).Do()
+		So(err, ShouldBeNil)
+		So(codes, ShouldHaveLength, 2)
+
+		Convey(".TournamentCode() returns created code", func() {
+			code, err := client.TournamentCode(ctx, codes[0]).Do()
+			So(err, ShouldBeNil)
+			So(code.Code, ShouldEqual, codes[0])
+			So(code.Region, ShouldEqual, "NA")
+			So(code.TournamentID, ShouldEqual, tournamentID)
+			So(code.TeamSize, ShouldEqual, 5)
+		})
+
+		Convey(".UpdateTournamentCode() updates code", func() {
+			err := client.UpdateTournamentCode(ctx, codes[0]).Body(&lol.TournamentCodeUpdateParameters{
+				AllowedParticipants: "1,2,3",
+				PickType:            "BLIND_PICK",
+			}).Do()
+			So(err, ShouldBeNil)
+
+			code, err := client.TournamentCode(ctx, codes[0]).Do()
+			So(err, ShouldBeNil)
+			So(code.Participants, ShouldResemble, []int64{1, 2, 3})
+			So(code.PickType, ShouldEqual, "BLIND_PICK")
+			So(code.Map, ShouldEqual, "SUMMONERS_RIFT")
+		})
+
+		Convey(".LobbyEvents() returns recorded events", func() {
+			So(fake.AddLobbyEvent(codes[1], &lol.LobbyEvent{EventType: "PracticeGameCreatedEvent"}), ShouldBeTrue)
+
+			events, err := client.LobbyEvents(ctx, codes[1]).Do()
+			So(err, ShouldBeNil)
+			So(events.EventList, ShouldHaveLength, 1)
+			So(events.EventList[0].EventType, ShouldEqual, "PracticeGameCreatedEvent")
+		})
+
+		Convey("Unknown code returns 404", func() {
+			_, err := client.TournamentCode(ctx, "unknown").Do()
+			So(err, ShouldHaveSameTypeAs, lol.HTTPError{})
+			So(err.(lol.HTTPError).Code, ShouldEqual, 404)
+		})
+
+		Convey("Request without api key returns 401", func() {
+			_, err := lol.New(loltest.ClientFactory(fake), "").TournamentCode(ctx, codes[0]).Do()
+			So(err, ShouldHaveSameTypeAs, lol.HTTPError{})
+			So(err.(lol.HTTPError).Code, ShouldEqual, 401)
+		})
+	})
+}
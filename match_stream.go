@@ -0,0 +1,259 @@
+package lol
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrStopStream can be returned by callbacks of MatchStream to stop reading a match early.
+// MatchCall.Stream returns nil error instead of it.
+var ErrStopStream = errors.New("go-lol: stop stream")
+
+// MatchStream is callbacks of MatchCall.Stream. Nil callbacks are ignored.
+type MatchStream struct {
+	// Event is called for each event of a frame, before Frame is called for the frame.
+	Event func(*Event) error
+	// Frame is called after each frame is read. Events of the frame are passed to Event instead of Frame.Events.
+	Frame func(*Frame) error
+}
+
+// Stream is like Do, but calls back s for each frame and event of the timeline while the response is read,
+// so that the whole timeline is never kept in memory. Timeline is requested even if IncludeTimeline is not set.
+//
+// The returned match has no Timeline.Frames. If a callback returns ErrStopStream,
+// fields located after the timeline in the response may be missing.
+func (c *MatchCall) Stream(s MatchStream) (*MatchDetail, error) {
+	c.IncludeTimeline(true)
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	res, err := c.doRequest()
+	if res != nil && res.Body != nil {
+		defer closeBody(res)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := verifyResponse(res); err != nil {
+		return nil, err
+	}
+	return streamMatch(res.Body, s)
+}
+
+// Stream calls back s with the match set by SetMatch.
+func (c fakeMatchCall) Stream(s MatchStream) (*MatchDetail, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	v, err := c.fake.result("Match")
+	if err != nil {
+		return nil, err
+	}
+	ret, _ := v.(*MatchDetail)
+	return replayMatch(ret, s)
+}
+
+func (s MatchStream) event(ev *Event) error {
+	if s.Event == nil {
+		return nil
+	}
+	return s.Event(ev)
+}
+
+func (s MatchStream) frame(f *Frame) error {
+	if s.Frame == nil {
+		return nil
+	}
+	return s.Frame(f)
+}
+
+// replayMatch calls back s with a match set to Fake.
+func replayMatch(m *MatchDetail, s MatchStream) (*MatchDetail, error) {
+	if m == nil {
+		return nil, nil
+	}
+	ret := *m
+	if m.Timeline == nil {
+		return &ret, nil
+	}
+	timeline := *m.Timeline
+	timeline.Frames = nil
+	ret.Timeline = &timeline
+
+	err := func() error {
+		for _, f := range m.Timeline.Frames {
+			for _, ev := range f.Events {
+				if err := s.event(ev); err != nil {
+					return err
+				}
+			}
+			frame := *f
+			frame.Events = nil
+			if err := s.frame(&frame); err != nil {
+				return err
+			}
+		}
+		return nil
+	}()
+	if err != nil && err != ErrStopStream {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+func streamMatch(r io.Reader, s MatchStream) (*MatchDetail, error) {
+	dec := json.NewDecoder(r)
+
+	var timeline *Timeline
+	rest, err := streamObject(dec, map[string]func() error{
+		"timeline": func() (err error) {
+			timeline, err = streamTimeline(dec, s)
+			return err
+		},
+	})
+	if err != nil && err != ErrStopStream {
+		return nil, err
+	}
+
+	ret := &MatchDetail{}
+	if rest != nil {
+		if err := json.Unmarshal(rest, ret); err != nil {
+			return nil, err
+		}
+	}
+	ret.Timeline = timeline
+	return ret, nil
+}
+
+func streamTimeline(dec *json.Decoder, s MatchStream) (*Timeline, error) {
+	rest, err := streamObject(dec, map[string]func() error{
+		"frames": func() error {
+			return streamArray(dec, func() error {
+				return streamFrame(dec, s)
+			})
+		},
+	})
+	if rest == nil {
+		return nil, err
+	}
+
+	ret := &Timeline{}
+	if uerr := json.Unmarshal(rest, ret); uerr != nil && err == nil {
+		err = uerr
+	}
+	return ret, err
+}
+
+func streamFrame(dec *json.Decoder, s MatchStream) error {
+	rest, err := streamObject(dec, map[string]func() error{
+		"events": func() error {
+			return streamArray(dec, func() error {
+				var ev *Event
+				if err := dec.Decode(&ev); err != nil || ev == nil {
+					return err
+				}
+				return s.event(ev)
+			})
+		},
+	})
+	if err != nil || rest == nil {
+		return err
+	}
+
+	frame := &Frame{}
+	if err := json.Unmarshal(rest, frame); err != nil {
+		return err
+	}
+	return s.frame(frame)
+}
+
+// streamObject reads an object, calling stream[key] to read the value of key (See streamOf).
+// Other members are returned as an object, which is nil if the value is null.
+// On errors, members read so far are returned.
+func streamObject(dec *json.Decoder, stream map[string]func() error) ([]byte, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	if tok == nil {
+		return nil, nil
+	}
+	if tok != json.Delim('{') {
+		return nil, fmt.Errorf("go-lol: expected object but got %v", tok)
+	}
+
+	var rest bytes.Buffer
+	rest.WriteByte('{')
+	closeRest := func() []byte {
+		rest.WriteByte('}')
+		return rest.Bytes()
+	}
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return closeRest(), err
+		}
+		key, _ := tok.(string)
+
+		if f := streamOf(stream, key); f != nil {
+			if err := f(); err != nil {
+				return closeRest(), err
+			}
+			continue
+		}
+
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return closeRest(), err
+		}
+		if rest.Len() > 1 {
+			rest.WriteByte(',')
+		}
+		k, _ := json.Marshal(key)
+		rest.Write(k)
+		rest.WriteByte(':')
+		rest.Write(raw)
+	}
+	if _, err := dec.Token(); err != nil {
+		return closeRest(), err
+	}
+	return closeRest(), nil
+}
+
+// streamOf returns the function of stream for key, matching keys case-insensitively like
+// UnmarshalJSON of response classes does, or nil if there is none.
+func streamOf(stream map[string]func() error, key string) func() error {
+	if f, ok := stream[key]; ok {
+		return f
+	}
+	for k, f := range stream {
+		if strings.EqualFold(k, key) {
+			return f
+		}
+	}
+	return nil
+}
+
+// streamArray reads an array, calling elem to read each element.
+func streamArray(dec *json.Decoder, elem func() error) error {
+	tok, err := dec.Token()
+	if err != nil || tok == nil {
+		return err
+	}
+	if tok != json.Delim('[') {
+		return fmt.Errorf("go-lol: expected array but got %v", tok)
+	}
+	for dec.More() {
+		if err := elem(); err != nil {
+			return err
+		}
+	}
+	_, err = dec.Token()
+	return err
+}
@@ -0,0 +1,91 @@
+package lol_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	lol "github.com/kdy1997/go-lol"
+	"github.com/kdy1997/go-lol/loltest"
+	. "github.com/smartystreets/goconvey/convey"
+	"golang.org/x/net/context"
+)
+
+func TestMatchStream(t *testing.T) {
+	Convey("MatchCall.Stream", t, func() {
+		match := filled(&lol.MatchDetail{}, 3).(*lol.MatchDetail)
+		var query string
+		keys := strings.NewReplacer() // rewrites keys of the response
+		client := lol.New(loltest.ClientFactory(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			query = r.URL.Query().Get("includeTimeline")
+			data, _ := json.Marshal(match)
+			w.Write([]byte(keys.Replace(string(data))))
+		})), "test-key")
+
+		var frames []*lol.Frame
+		var events []*lol.Event
+		s := lol.MatchStream{
+			Event: func(ev *lol.Event) error {
+				events = append(events, ev)
+				return nil
+			},
+			Frame: func(f *lol.Frame) error {
+				frames = append(frames, f)
+				return nil
+			},
+		}
+
+		check := func(got *lol.MatchDetail) {
+			So(frames, ShouldHaveLength, 3)
+			So(events, ShouldHaveLength, 9)
+			So(events[3], ShouldResemble, match.Timeline.Frames[1].Events[0])
+			So(frames[1].Events, ShouldBeNil)
+			So(frames[1].ParticipantFrames, ShouldResemble, match.Timeline.Frames[1].ParticipantFrames)
+
+			So(got.Timeline.Frames, ShouldBeNil)
+			So(got.Timeline.FrameInterval, ShouldEqual, match.Timeline.FrameInterval)
+			got.Timeline = match.Timeline
+			So(got, ShouldResemble, match)
+		}
+
+		Convey("Calls back every frame and event", func() {
+			got, err := client.Match(context.TODO(), lol.NA, 1).Stream(s)
+			So(err, ShouldBeNil)
+			So(query, ShouldEqual, "true")
+			check(got)
+		})
+
+		Convey("Matches keys case-insensitively like Do", func() {
+			keys = strings.NewReplacer(`"timeline":`, `"Timeline":`, `"frames":`, `"FRAMES":`, `"events":`, `"Events":`)
+			got, err := client.Match(context.TODO(), lol.NA, 1).Stream(s)
+			So(err, ShouldBeNil)
+			check(got)
+		})
+
+		Convey("Stops with ErrStopStream", func() {
+			s.Event = func(ev *lol.Event) error {
+				events = append(events, ev)
+				if len(events) == 2 {
+					return lol.ErrStopStream
+				}
+				return nil
+			}
+			got, err := client.Match(context.TODO(), lol.NA, 1).Stream(s)
+			So(err, ShouldBeNil)
+			So(got.MatchID, ShouldEqual, match.MatchID)
+			So(events, ShouldHaveLength, 2)
+			So(frames, ShouldBeEmpty)
+		})
+
+		Convey("Replays a match set to Fake", func() {
+			fake := lol.NewFake()
+			fake.SetMatch(match, nil)
+
+			got, err := fake.Match(context.TODO(), lol.NA, 1).Stream(s)
+			So(err, ShouldBeNil)
+			check(got)
+			So(match.Timeline.Frames, ShouldHaveLength, 3)
+		})
+	})
+}
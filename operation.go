@@ -0,0 +1,140 @@
+package lol
+
+import "github.com/kdy1997/go-lol/internal/uritemplates"
+
+// Operation describes an api operation. See Operations.
+type Operation struct {
+	// Name is the name of method creating a call (e.g. "Summoners").
+	Name string
+	// Description is the summary of the operation in riot document.
+	Description string
+
+	Method string // HTTP method (e.g. "GET")
+	// Path is an uri template of request path (e.g. "/api/lol/{region}/v1.4/summoner/{summonerIds}").
+	Path string
+	// BaseURL is empty if the request is sent to the host of a region (See Region.Host).
+	BaseURL string
+	// Routing is how the request is routed with PlatformHosts.
+	Routing Routing
+
+	ResourceID      string // e.g. "summoner"
+	ResourceVersion string // e.g. "v1.4"
+
+	// Regions is regions supporting the operation, or nil if the operation does not take a region.
+	Regions []Region
+	// NeedAPIKey is false if the operation is called by StaticClient.
+	NeedAPIKey bool
+
+	RateLimitNotes string
+	// Errors is response errors documented by riot.
+	Errors []OperationError
+
+	template *uritemplates.URITemplate // parsed Path
+}
+
+// Routing is how a request is routed to a host with PlatformHosts.
+type Routing int
+
+const (
+	// FixedRouting sends requests to BaseURL with any host scheme.
+	FixedRouting Routing = iota
+	// PlatformRouting sends requests to the host of a platform (See Region.PlatformHost).
+	PlatformRouting
+	// ClusterRouting sends requests to the host of a continental cluster (See Region.Cluster).
+	ClusterRouting
+)
+
+// OperationError is a documented response error of an operation.
+type OperationError struct {
+	Code   int
+	Reason string
+}
+
+// Operations returns every operation, in the order of riot document.
+func Operations() []Operation {
+	ret := make([]Operation, len(operations))
+	for i, op := range operations {
+		ret[i] = op.clone()
+	}
+	return ret
+}
+
+// LookupOperation returns an operation by its name (e.g. "Summoners").
+func LookupOperation(name string) (Operation, bool) {
+	if op := lookupOperation(name); op != nil {
+		return op.clone(), true
+	}
+	return Operation{}, false
+}
+
+// lookupOperation returns the operation in the table, which must not be modified, or nil.
+func lookupOperation(name string) *Operation {
+	for i := range operations {
+		if operations[i].Name == name {
+			return &operations[i]
+		}
+	}
+	return nil
+}
+
+// clone returns a copy of op, which does not share slices with the table.
+func (op Operation) clone() Operation {
+	if op.Regions != nil {
+		op.Regions = append(make([]Region, 0, len(op.Regions)), op.Regions...)
+	}
+	if op.Errors != nil {
+		op.Errors = append(make([]OperationError, 0, len(op.Errors)), op.Errors...)
+	}
+	return op
+}
+
+// SupportedRegions returns regions supporting the operation named op (e.g. "Summoners"),
+// or nil if there is no such operation or the operation does not take a region.
+func SupportedRegions(op string) []Region {
+	o := lookupOperation(op)
+	if o == nil || o.Regions == nil {
+		return nil
+	}
+	return append([]Region(nil), o.Regions...)
+}
+
+// Supports reports whether the operation named op (e.g. "Summoners") works for the region.
+// Operations not taking a region work for any region.
+func (r Region) Supports(op string) bool {
+	o := lookupOperation(op)
+	if o == nil {
+		return false
+	}
+	if o.Regions == nil {
+		return true
+	}
+	for _, supported := range o.Regions {
+		if supported == r {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchOperation returns the operation of a request and values of its path parameters
+// (e.g. {"region": "na", "summonerIds": "1,2"}), so that a router, mock server or proxy can
+// map an incoming request back to an operation. path is the escaped request path
+// (e.g. URL.EscapedPath()), without BaseURL nor host.
+//
+// If several operations match, the one with the longest literal path is returned.
+func MatchOperation(method, path string) (Operation, map[string]string, bool) {
+	var (
+		ret    Operation
+		params map[string]string
+		best   = -1
+	)
+	for _, op := range operations {
+		if op.Method != method || op.template.LiteralLen() <= best {
+			continue
+		}
+		if values, ok := op.template.Match(path); ok {
+			ret, params, best = op, values, op.template.LiteralLen()
+		}
+	}
+	return ret.clone(), params, best != -1
+}
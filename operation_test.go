@@ -0,0 +1,135 @@
+package lol_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	lol "github.com/kdy1997/go-lol"
+	. "github.com/smartystreets/goconvey/convey"
+	"golang.org/x/net/context"
+)
+
+func TestOperations(t *testing.T) {
+	Convey("Operations", t, func() {
+		ops := lol.Operations()
+		So(len(ops), ShouldBeGreaterThan, 0)
+
+		seen := make(map[string]bool)
+		for _, op := range ops {
+			So(seen[op.Name], ShouldBeFalse)
+			seen[op.Name] = true
+		}
+
+		Convey("LookupOperation returns metadata", func() {
+			op, ok := lol.LookupOperation("Summoners")
+			So(ok, ShouldBeTrue)
+			So(op.Method, ShouldEqual, "GET")
+			So(op.Path, ShouldEqual, "/api/lol/{region}/v1.4/summoner/{summonerIds}")
+			So(op.BaseURL, ShouldEqual, "")
+			So(op.ResourceID, ShouldEqual, "summoner")
+			So(op.ResourceVersion, ShouldEqual, "v1.4")
+			So(op.Routing, ShouldEqual, lol.PlatformRouting)
+			So(op.Regions, ShouldContain, lol.NA)
+			So(op.NeedAPIKey, ShouldBeTrue)
+			So(op.Errors, ShouldContain, lol.OperationError{Code: 404, Reason: "No summoner data found for any specified inputs"})
+		})
+
+		Convey("Returned operations do not share slices with the table", func() {
+			ops := lol.Operations()
+			ops[0].Regions[0] = lol.PBE
+			ops[0].Errors[0].Code = 0
+			op, _ := lol.LookupOperation(ops[0].Name)
+			So(op.Regions[0], ShouldNotEqual, lol.PBE)
+			So(op.Errors[0].Code, ShouldNotEqual, 0)
+
+			op.Regions[0] = lol.PBE
+			So(lol.Operations()[0].Regions[0], ShouldNotEqual, lol.PBE)
+		})
+
+		Convey("LookupOperation returns false for unknown name", func() {
+			_, ok := lol.LookupOperation("Unknown")
+			So(ok, ShouldBeFalse)
+		})
+
+		Convey("Static operations use global host", func() {
+			op, ok := lol.LookupOperation("Shards")
+			So(ok, ShouldBeTrue)
+			So(op.NeedAPIKey, ShouldBeFalse)
+			So(op.BaseURL, ShouldEqual, "https://status.leagueoflegends.com")
+		})
+	})
+}
+
+func TestSupportedRegions(t *testing.T) {
+	Convey("SupportedRegions", t, func() {
+		for _, op := range lol.Operations() {
+			So(lol.SupportedRegions(op.Name), ShouldResemble, op.Regions)
+		}
+		So(lol.SupportedRegions("Unknown"), ShouldBeNil)
+
+		So(lol.NA.Supports("ChampionStatuses"), ShouldBeTrue)
+		So(lol.PBE.Supports("ChampionStatuses"), ShouldBeFalse)
+		So(lol.NA.Supports("Unknown"), ShouldBeFalse)
+
+		Convey("Modifying the result does not change supported regions", func() {
+			regions := lol.SupportedRegions("ChampionStatuses")
+			regions[0] = lol.PBE
+			So(lol.PBE.Supports("ChampionStatuses"), ShouldBeFalse)
+			So(lol.SupportedRegions("ChampionStatuses")[0], ShouldNotEqual, lol.PBE)
+		})
+
+		Convey("Operations not taking a region work for any region", func() {
+			So(lol.SupportedRegions("TournamentCode"), ShouldBeNil)
+			So(lol.PBE.Supports("TournamentCode"), ShouldBeTrue)
+			So(lol.PBE.Supports("Shard"), ShouldBeTrue)
+		})
+
+		Convey("Calls report the operation and the region", func() {
+			_, err := lol.New(nil, "key").ChampionStatuses(context.TODO(), lol.PBE).Do()
+			So(err, ShouldResemble, &lol.UnsupportedRegionError{Operation: "ChampionStatuses", Region: lol.PBE})
+			So(err.Error(), ShouldEqual, "go-lol: ChampionStatuses does not work for region pbe")
+			So(errors.Is(err, lol.ErrNotSupportedRegion), ShouldBeTrue)
+		})
+	})
+}
+
+func TestMatchOperation(t *testing.T) {
+	Convey("MatchOperation", t, func() {
+		Convey("Returns an operation and its path parameters", func() {
+			op, params, ok := lol.MatchOperation("GET", "/api/lol/na/v1.4/summoner/585897%2C1")
+			So(ok, ShouldBeTrue)
+			So(op.Name, ShouldEqual, "Summoners")
+			So(params, ShouldResemble, map[string]string{"region": "na", "summonerIds": "585897,1"})
+		})
+
+		Convey("Prefers the most specific path", func() {
+			// matches SummonerNames as well, with summonerIds "by-name".
+			op, params, ok := lol.MatchOperation("GET", "/api/lol/na/v1.4/summoner/by-name/name")
+			So(ok, ShouldBeTrue)
+			So(op.Name, ShouldEqual, "SummonersByName")
+			So(params["summonerNames"], ShouldEqual, "name")
+
+			op, params, ok = lol.MatchOperation("GET", "/api/lol/na/v1.4/summoner/1/name")
+			So(ok, ShouldBeTrue)
+			So(op.Name, ShouldEqual, "SummonerNames")
+			So(params["summonerIds"], ShouldEqual, "1")
+		})
+
+		Convey("Every operation matches its expanded path", func() {
+			for _, op := range lol.Operations() {
+				path := strings.NewReplacer("{region}", "na", "{platformId}", "NA1", "{", "", "}", "").Replace(op.Path)
+				matched, _, ok := lol.MatchOperation(op.Method, path)
+				So(ok, ShouldBeTrue)
+				So(matched.Path, ShouldEqual, op.Path)
+			}
+		})
+
+		Convey("Returns false for unknown paths and methods", func() {
+			_, _, ok := lol.MatchOperation("GET", "/api/lol/na/v1.4/unknown")
+			So(ok, ShouldBeFalse)
+			_, _, ok = lol.MatchOperation("DELETE", "/api/lol/na/v1.4/summoner/1")
+			So(ok, ShouldBeFalse)
+		})
+	})
+}
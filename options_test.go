@@ -0,0 +1,181 @@
+package lol_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	lol "github.com/kdy1997/go-lol"
+	"github.com/kdy1997/go-lol/loltest"
+	. "github.com/smartystreets/goconvey/convey"
+	"golang.org/x/net/context"
+)
+
+func TestCallOptions(t *testing.T) {
+	Convey("Options of calls", t, func() {
+		var requests []*http.Request
+		var bodies []string
+		status := []int{}
+		reply := "{}"
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests = append(requests, r)
+			if r.Body != nil {
+				body, _ := ioutil.ReadAll(r.Body)
+				bodies = append(bodies, string(body))
+			}
+			if len(status) != 0 {
+				w.WriteHeader(status[0])
+				status = status[1:]
+				return
+			}
+			w.Write([]byte(reply))
+		})
+		client := lol.New(loltest.ClientFactory(handler), "key")
+
+		Convey("Add headers", func() {
+			_, err := client.Summoners(context.TODO(), lol.NA, []int64{1}).
+				Options(lol.WithHeader("X-Trace", "a"), lol.WithHeader("X-Trace", "b"), lol.WithoutCache()).Do()
+			So(err, ShouldBeNil)
+			So(requests, ShouldHaveLength, 1)
+			So(requests[0].Header["X-Trace"], ShouldResemble, []string{"a", "b"})
+			So(requests[0].Header.Get("Cache-Control"), ShouldEqual, "no-cache")
+		})
+
+		Convey("Retry with the same body", func() {
+			status = []int{http.StatusServiceUnavailable, http.StatusTooManyRequests}
+			reply = "1"
+			_, err := client.CreateTournamentProvider(context.TODO()).
+				Body(&lol.ProviderRegistrationParameters{Region: "NA", URL: "http://example.com"}).
+				Options(lol.WithRetry(lol.RetryPolicy{MaxRetries: 2, Backoff: time.Millisecond, NonIdempotent: true})).Do()
+			So(err, ShouldBeNil)
+			So(bodies, ShouldHaveLength, 3)
+			So(bodies[2], ShouldEqual, bodies[0])
+			So(bodies[0], ShouldContainSubstring, "example.com")
+		})
+
+		Convey("Do not retry other methods than GET and HEAD by default", func() {
+			status = []int{http.StatusServiceUnavailable}
+			_, err := client.CreateTournamentProvider(context.TODO()).
+				Body(&lol.ProviderRegistrationParameters{Region: "NA", URL: "http://example.com"}).
+				Options(lol.WithRetry(lol.RetryPolicy{MaxRetries: 2, Backoff: time.Millisecond})).Do()
+			So(err, ShouldNotBeNil)
+			So(bodies, ShouldHaveLength, 1)
+		})
+
+		Convey("Wait DefaultBackoff without Backoff", func() {
+			status = []int{500, 500}
+			ctx, cancel := context.WithTimeout(context.TODO(), lol.DefaultBackoff/10)
+			defer cancel()
+			_, err := client.Summoners(ctx, lol.NA, []int64{1}).
+				Options(lol.WithRetry(lol.RetryPolicy{MaxRetries: 1})).Do()
+			So(err, ShouldEqual, context.DeadlineExceeded)
+			So(requests, ShouldHaveLength, 1)
+		})
+
+		Convey("Give up after MaxRetries", func() {
+			status = []int{500, 500, 500}
+			_, err := client.Summoners(context.TODO(), lol.NA, []int64{1}).
+				Options(lol.WithRetry(lol.RetryPolicy{MaxRetries: 1, Backoff: time.Millisecond})).Do()
+			So(err, ShouldHaveSameTypeAs, lol.HTTPError{})
+			So(err.(lol.HTTPError).Code, ShouldEqual, 500)
+			So(requests, ShouldHaveLength, 2)
+		})
+
+		Convey("Do not retry client errors", func() {
+			status = []int{404}
+			_, err := client.Summoners(context.TODO(), lol.NA, []int64{1}).
+				Options(lol.WithRetry(lol.RetryPolicy{MaxRetries: 3})).Do()
+			So(err, ShouldNotBeNil)
+			So(requests, ShouldHaveLength, 1)
+		})
+
+		Convey("Use a http client of the call", func() {
+			var other []*http.Request
+			httpClient := loltest.ClientFactory(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				other = append(other, r)
+				w.Write([]byte("{}"))
+			}))(context.TODO())
+			_, err := client.Summoners(context.TODO(), lol.NA, []int64{1}).Options(lol.WithHTTPClient(httpClient)).Do()
+			So(err, ShouldBeNil)
+			So(other, ShouldHaveLength, 1)
+			So(requests, ShouldBeEmpty)
+		})
+
+		Convey("Time out", func() {
+			slow := lol.New(loltest.ClientFactory(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				select {
+				case <-time.After(time.Second):
+				case <-r.Context().Done():
+				}
+			})), "key")
+			start := time.Now()
+			_, err := slow.Summoners(context.TODO(), lol.NA, []int64{1}).Options(lol.WithTimeout(10 * time.Millisecond)).Do()
+			So(err, ShouldNotBeNil)
+			So(time.Since(start), ShouldBeLessThan, time.Second)
+		})
+
+		Convey("Pass priority to ClientFactory", func() {
+			var priority lol.Priority
+			factory := loltest.ClientFactory(handler)
+			client := lol.New(func(ctx context.Context) *http.Client {
+				priority = lol.PriorityFromContext(ctx)
+				return factory(ctx)
+			}, "key")
+			_, err := client.Summoners(context.TODO(), lol.NA, []int64{1}).Options(lol.WithPriority(lol.HighPriority)).Do()
+			So(err, ShouldBeNil)
+			So(priority, ShouldEqual, lol.HighPriority)
+			So(lol.PriorityFromContext(requests[0].Context()), ShouldEqual, lol.HighPriority)
+		})
+	})
+}
+
+func TestCallURL(t *testing.T) {
+	Convey("URL returns the url of a call with api key redacted", t, func() {
+		var sent int
+		client := lol.New(loltest.ClientFactory(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			sent++
+		})), "secret-key")
+
+		u, err := client.Summoners(context.TODO(), lol.NA, []int64{1, 2}).URL()
+		So(err, ShouldBeNil)
+		So(u, ShouldEqual, "https://na.api.pvp.net/api/lol/na/v1.4/summoner/1%2C2?api_key=REDACTED")
+
+		u, err = client.MatchesBySummonerID(context.TODO(), lol.KR, 1).BeginIndex(10).URL()
+		So(err, ShouldBeNil)
+		So(u, ShouldStartWith, "https://kr.api.pvp.net/api/lol/kr/v2.2/matchlist/by-summoner/1?")
+		So(u, ShouldContainSubstring, "beginIndex=10")
+		So(u, ShouldNotContainSubstring, "secret-key")
+
+		u, err = client.Shards(context.TODO()).URL()
+		So(err, ShouldBeNil)
+		So(u, ShouldEqual, "https://status.leagueoflegends.com/shards?")
+
+		_, err = client.ChampionStatuses(context.TODO(), lol.PBE).URL()
+		So(err, ShouldHaveSameTypeAs, &lol.UnsupportedRegionError{})
+		So(sent, ShouldEqual, 0)
+	})
+
+	Convey("Clients created WithDryRun log requests instead of sending them", t, func() {
+		var sent int
+		var logs []string
+		client := lol.New(loltest.ClientFactory(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			sent++
+		})), "secret-key", lol.WithDryRun(func(format string, args ...interface{}) {
+			logs = append(logs, fmt.Sprintf(format, args...))
+		}))
+
+		_, err := client.Summoners(context.TODO(), lol.NA, []int64{1}).Do()
+		So(err, ShouldEqual, lol.ErrDryRun)
+		err = client.UpdateTournamentCode(context.TODO(), "code").
+			Body(&lol.TournamentCodeUpdateParameters{MapType: "SUMMONERS_RIFT"}).Do()
+		So(err, ShouldEqual, lol.ErrDryRun)
+
+		So(sent, ShouldEqual, 0)
+		So(logs, ShouldResemble, []string{
+			"go-lol: dry run: GET https://na.api.pvp.net/api/lol/na/v1.4/summoner/1?api_key=REDACTED",
+			`go-lol: dry run: PUT https://global.api.pvp.net/tournament/public/v1/code/code?api_key=REDACTED {"allowedParticipants":"","mapType":"SUMMONERS_RIFT","pickType":"","spectatorType":""}`,
+		})
+	})
+}
@@ -1,6 +1,11 @@
 package lol
 
-import "errors"
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+)
 
 var (
 	// ErrNoSuchRegion is returned if region not found.
@@ -61,6 +66,7 @@ var regionByName = map[string]Region{
 
 var regionByPlatformID = map[string]Region{
 	"pbe":  PBE,
+	"pbe1": PBE, // See platformHostIDByRegion.
 	"na1":  NA,
 	"euw1": EUW,
 	"eun1": EUNE,
@@ -106,6 +112,12 @@ var platformIDByRegion = map[Region]string{
 	JP:     "jp1",
 }
 
+// platformHostIDByRegion overrides PlatformID in PlatformHost for regions whose
+// platform host differs from the platform id taken by legacy endpoints.
+var platformHostIDByRegion = map[Region]string{
+	PBE: "pbe1",
+}
+
 var hostByRegion = map[Region]string{
 	Global: "global.api.pvp.net",
 	PBE:    "pbe.api.pvp.net",
@@ -122,6 +134,37 @@ var hostByRegion = map[Region]string{
 	JP:     "jp.api.pvp.net",
 }
 
+// Cluster is a continental routing cluster, which serves apis shared by regions of a continent.
+type Cluster string
+
+const (
+	Americas Cluster = "americas"
+	Europe   Cluster = "europe"
+	Asia     Cluster = "asia"
+)
+
+// Host returns hostname of the cluster for api call.
+func (c Cluster) Host() string {
+	return string(c) + ".api.riotgames.com"
+}
+
+// Global, which is not a platform, is routed to Americas, where tournament apis are served.
+var clusterByRegion = map[Region]Cluster{
+	Global: Americas,
+	PBE:    Americas,
+	NA:     Americas,
+	EUW:    Europe,
+	EUNE:   Europe,
+	KR:     Asia,
+	BR:     Americas,
+	TR:     Europe,
+	RU:     Europe,
+	LAS:    Americas,
+	LAN:    Americas,
+	OCE:    Americas,
+	JP:     Asia,
+}
+
 // RegionByName gets a region by name.
 func RegionByName(name string) (Region, error) {
 	r, ok := regionByName[name]
@@ -140,6 +183,15 @@ func RegionByPlatformID(id string) (Region, error) {
 	return r, nil
 }
 
+// ParseRegion gets a region by name or platform id, ignoring case. e.g. "NA", "na" and "na1".
+func ParseRegion(s string) (Region, error) {
+	s = strings.ToLower(s)
+	if r, ok := regionByName[s]; ok {
+		return r, nil
+	}
+	return RegionByPlatformID(s)
+}
+
 // Name returns name of the region.
 func (r Region) Name() string {
 	return nameByRegion[r]
@@ -165,6 +217,23 @@ func (r Region) Host() string {
 	return hostByRegion[r]
 }
 
+// PlatformHost returns hostname of the platform of the region (e.g. "na1.api.riotgames.com"),
+// or empty string for Global.
+func (r Region) PlatformHost() string {
+	if id, ok := platformHostIDByRegion[r]; ok {
+		return id + ".api.riotgames.com"
+	}
+	if r.PlatformID() == "" {
+		return ""
+	}
+	return r.PlatformID() + ".api.riotgames.com"
+}
+
+// Cluster returns the continental routing cluster of the region.
+func (r Region) Cluster() Cluster {
+	return clusterByRegion[r]
+}
+
 func (r Region) baseURL() string {
 	return "https://" + r.Host()
 }
@@ -173,3 +242,95 @@ func (r Region) baseURL() string {
 func Regions() []Region {
 	return regions[:]
 }
+
+// MarshalText implements encoding.TextMarshaler. The region is encoded by its name.
+func (r Region) MarshalText() ([]byte, error) {
+	name, ok := nameByRegion[r]
+	if !ok {
+		return nil, ErrNoSuchRegion
+	}
+	return []byte(name), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. It accepts what ParseRegion does.
+func (r *Region) UnmarshalText(text []byte) error {
+	region, err := ParseRegion(string(text))
+	if err != nil {
+		return err
+	}
+	*r = region
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler. The region is encoded as a string of its name.
+func (r Region) MarshalJSON() ([]byte, error) {
+	text, err := r.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts what ParseRegion does,
+// and numbers of regions, which are encoded by older versions. null leaves r unchanged, like encoding/json.
+func (r *Region) UnmarshalJSON(data []byte) error {
+	if string(bytes.TrimSpace(data)) == "null" {
+		return nil
+	}
+
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		return r.UnmarshalText([]byte(name))
+	}
+
+	var n int32
+	if err := json.Unmarshal(data, &n); err != nil {
+		return err
+	}
+	if _, ok := nameByRegion[Region(n)]; !ok {
+		return ErrNoSuchRegion
+	}
+	*r = Region(n)
+	return nil
+}
+
+// Set implements flag.Value. It accepts what ParseRegion does.
+func (r *Region) Set(s string) error {
+	return r.UnmarshalText([]byte(s))
+}
+
+// resolveRegion returns the region of the first of values which ParseRegion accepts.
+// Response classes spell regions in several ways, e.g. "NA", "na" and "NA1".
+func resolveRegion(values ...string) (Region, error) {
+	for _, v := range values {
+		if r, err := ParseRegion(v); err == nil {
+			return r, nil
+		}
+	}
+	return 0, ErrNoSuchRegion
+}
+
+// ResolveRegion returns the region where the match was played, from PlatformID or Region.
+func (m *MatchRef) ResolveRegion() (Region, error) {
+	return resolveRegion(m.PlatformID, m.Region)
+}
+
+// ResolveRegion returns the region where the match was played, from PlatformID or Region.
+func (m *MatchDetail) ResolveRegion() (Region, error) {
+	return resolveRegion(m.PlatformID, m.Region)
+}
+
+// ResolveRegion returns the region of the game, from PlatformID.
+func (g *CurrentGameInfo) ResolveRegion() (Region, error) {
+	return resolveRegion(g.PlatformID)
+}
+
+// ResolveRegion returns the region of the shard, from RegionTag or Slug.
+func (s *Shard) ResolveRegion() (Region, error) {
+	return resolveRegion(s.RegionTag, s.Slug)
+}
+
+// ResolveRegion returns the region of the shard, from RegionTag or Slug.
+func (s *ShardStatus) ResolveRegion() (Region, error) {
+	return resolveRegion(s.RegionTag, s.Slug)
+}
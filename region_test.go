@@ -0,0 +1,143 @@
+package lol_test
+
+import (
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	lol "github.com/kdy1997/go-lol"
+	"github.com/kdy1997/go-lol/loltest"
+	. "github.com/smartystreets/goconvey/convey"
+	"golang.org/x/net/context"
+)
+
+func TestRegionEncoding(t *testing.T) {
+	Convey("ParseRegion accepts names, platform ids and upper case", t, func() {
+		for _, s := range []string{"NA", "na", "na1", "NA1"} {
+			r, err := lol.ParseRegion(s)
+			So(err, ShouldBeNil)
+			So(r, ShouldEqual, lol.NA)
+		}
+		r, err := lol.ParseRegion("la2")
+		So(err, ShouldBeNil)
+		So(r, ShouldEqual, lol.LAS)
+
+		for _, s := range []string{"", "na2", "north america"} {
+			_, err := lol.ParseRegion(s)
+			So(err, ShouldEqual, lol.ErrNoSuchRegion)
+		}
+	})
+
+	Convey("Region round-trips through json", t, func() {
+		type config struct {
+			Region  lol.Region
+			Regions map[lol.Region]bool
+		}
+		data, err := json.Marshal(config{Region: lol.EUNE, Regions: map[lol.Region]bool{lol.KR: true}})
+		So(err, ShouldBeNil)
+		So(string(data), ShouldEqual, `{"Region":"eune","Regions":{"kr":true}}`)
+
+		var c config
+		So(json.Unmarshal([]byte(`{"Region":"EUN1","Regions":{"KR":true}}`), &c), ShouldBeNil)
+		So(c.Region, ShouldEqual, lol.EUNE)
+		So(c.Regions, ShouldResemble, map[lol.Region]bool{lol.KR: true})
+
+		So(json.Unmarshal([]byte(`{"Region":10}`), &c), ShouldBeNil)
+		So(c.Region, ShouldEqual, lol.NA)
+
+		So(json.Unmarshal([]byte(`{"Region":null}`), &c), ShouldBeNil)
+		So(c.Region, ShouldEqual, lol.NA)
+
+		So(json.Unmarshal([]byte(`{"Region":"xx"}`), &c), ShouldNotBeNil)
+		So(json.Unmarshal([]byte(`{"Region":99}`), &c), ShouldNotBeNil)
+		_, err = json.Marshal(lol.Region(99))
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("Region is a flag.Value", t, func() {
+		r := lol.NA
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(ioutil.Discard)
+		fs.Var(&r, "region", "region")
+
+		So(fs.Parse([]string{"-region", "EUW"}), ShouldBeNil)
+		So(r, ShouldEqual, lol.EUW)
+		So(fs.Lookup("region").Value.String(), ShouldEqual, "euw")
+		So(fs.Parse([]string{"-region", "mars"}), ShouldNotBeNil)
+	})
+}
+
+func TestRegionHosts(t *testing.T) {
+	Convey("Regions have platform hosts and clusters", t, func() {
+		So(lol.NA.PlatformHost(), ShouldEqual, "na1.api.riotgames.com")
+		So(lol.EUNE.PlatformHost(), ShouldEqual, "eun1.api.riotgames.com")
+		So(lol.Global.PlatformHost(), ShouldBeEmpty)
+		So(lol.PBE.PlatformHost(), ShouldEqual, "pbe1.api.riotgames.com")
+		So(lol.PBE.PlatformID(), ShouldEqual, "pbe")
+		for _, id := range []string{"pbe", "PBE1"} {
+			r, err := lol.ParseRegion(id)
+			So(err, ShouldBeNil)
+			So(r, ShouldEqual, lol.PBE)
+		}
+		r, err := lol.RegionByPlatformID("pbe")
+		So(err, ShouldBeNil)
+		So(r, ShouldEqual, lol.PBE)
+		So(lol.NA.Cluster(), ShouldEqual, lol.Americas)
+		So(lol.TR.Cluster(), ShouldEqual, lol.Europe)
+		So(lol.JP.Cluster().Host(), ShouldEqual, "asia.api.riotgames.com")
+		for _, r := range lol.Regions() {
+			So(r.PlatformHost(), ShouldNotBeEmpty)
+			So(r.Cluster(), ShouldNotBeEmpty)
+		}
+	})
+
+	Convey("Clients send requests to hosts of the host scheme", t, func() {
+		var hosts []string
+		factory := loltest.ClientFactory(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hosts = append(hosts, r.Host)
+			w.Write([]byte("{}"))
+		}))
+		call := func(c *lol.Client) {
+			c.Summoners(context.TODO(), lol.KR, []int64{1}).Do()
+			c.Champions(context.TODO(), lol.KR).Do()
+			c.TournamentCode(context.TODO(), "code").Do()
+			c.Shards(context.TODO()).Do()
+		}
+
+		call(lol.New(factory, "key"))
+		So(hosts, ShouldResemble, []string{"kr.api.pvp.net", "global.api.pvp.net", "global.api.pvp.net", "status.leagueoflegends.com"})
+
+		hosts = nil
+		call(lol.New(factory, "key", lol.WithHostScheme(lol.PlatformHosts)))
+		So(hosts, ShouldResemble, []string{"kr.api.riotgames.com", "kr.api.riotgames.com", "americas.api.riotgames.com", "status.leagueoflegends.com"})
+	})
+}
+
+func TestResolveRegion(t *testing.T) {
+	Convey("Response classes resolve their regions", t, func() {
+		r, err := (&lol.MatchRef{PlatformID: "EUN1", Region: "EUNE"}).ResolveRegion()
+		So(err, ShouldBeNil)
+		So(r, ShouldEqual, lol.EUNE)
+
+		r, err = (&lol.MatchDetail{Region: "LAS"}).ResolveRegion()
+		So(err, ShouldBeNil)
+		So(r, ShouldEqual, lol.LAS)
+
+		r, err = (&lol.CurrentGameInfo{PlatformID: "OC1"}).ResolveRegion()
+		So(err, ShouldBeNil)
+		So(r, ShouldEqual, lol.OCE)
+
+		r, err = (&lol.Shard{RegionTag: "na1", Slug: "na"}).ResolveRegion()
+		So(err, ShouldBeNil)
+		So(r, ShouldEqual, lol.NA)
+
+		r, err = (&lol.ShardStatus{RegionTag: "unknown", Slug: "kr"}).ResolveRegion()
+		So(err, ShouldBeNil)
+		So(r, ShouldEqual, lol.KR)
+
+		_, err = (&lol.CurrentGameInfo{}).ResolveRegion()
+		So(err, ShouldEqual, lol.ErrNoSuchRegion)
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"strconv"
 	"strings"
@@ -42,6 +43,20 @@ func (sr *SpellRange) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// decodeResponse decodes the body of res into v.
+// Generated UnmarshalJSON of v is called directly, because json.Decoder scans the body once more before calling it.
+func decodeResponse(res *http.Response, v interface{}) error {
+	u, ok := v.(json.Unmarshaler)
+	if !ok {
+		return json.NewDecoder(res.Body).Decode(v)
+	}
+	data, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	return u.UnmarshalJSON(data)
+}
+
 // concat ids with ','
 func joinIDs(ids []int64) string {
 	var buf bytes.Buffer
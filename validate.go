@@ -0,0 +1,114 @@
+package lol
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParamError describes an invalid parameter of a call.
+type ParamError struct {
+	Param  string
+	Reason string
+}
+
+func (e ParamError) String() string {
+	return e.Param + ": " + e.Reason
+}
+
+// ValidationError is returned by Validate and Do of a call if its parameters
+// violate constraints documented by riot. It lists every problem found.
+type ValidationError struct {
+	Operation string // name of the operation (e.g. "Summoners")
+	Params    []ParamError
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Params))
+	for _, p := range e.Params {
+		msgs = append(msgs, p.String())
+	}
+	return fmt.Sprintf("go-lol: invalid %s call: %s", e.Operation, strings.Join(msgs, "; "))
+}
+
+// validator is used by generated Validate methods.
+// Every value is a parameter converted by convertToString, and "" means it's not set.
+type validator struct {
+	err ValidationError
+}
+
+func newValidator(operation string) *validator {
+	return &validator{err: ValidationError{Operation: operation}}
+}
+
+func (v *validator) errorf(param, format string, args ...interface{}) {
+	v.err.Params = append(v.err.Params, ParamError{
+		Param:  param,
+		Reason: fmt.Sprintf(format, args...),
+	})
+}
+
+// required checks a required parameter. For numbers, zero is treated as missing.
+func (v *validator) required(param, value string, isNumber bool) {
+	switch {
+	case value == "":
+		v.errorf(param, "required")
+	case isNumber && value == "0":
+		v.errorf(param, "must not be zero")
+	}
+}
+
+// maxItems checks length of a comma-separated list.
+func (v *validator) maxItems(param, value string, max int) {
+	if value == "" {
+		return
+	}
+	if n := len(strings.Split(value, ",")); n > max {
+		v.errorf(param, "%d items exceed the maximum %d", n, max)
+	}
+}
+
+// enum checks value is one of allowed. If multiple is true, value is a comma-separated list.
+func (v *validator) enum(param, value string, multiple bool, allowed ...string) {
+	if value == "" {
+		return
+	}
+	vals := []string{value}
+	if multiple {
+		vals = strings.Split(value, ",")
+	}
+	for _, val := range vals {
+		if !contains(allowed, val) {
+			v.errorf(param, "unsupported value %q", val)
+		}
+	}
+}
+
+// together checks either every parameter is set or none is.
+func (v *validator) together(params []string, values ...string) {
+	set := 0
+	for _, val := range values {
+		if val != "" {
+			set++
+		}
+	}
+	if set != 0 && set != len(values) {
+		v.errorf(strings.Join(params, ", "), "must be set together")
+	}
+}
+
+func (v *validator) result() error {
+	if len(v.err.Params) == 0 {
+		return nil
+	}
+	err := v.err
+	return &err
+}
+
+func contains(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+package lol_test
+
+import (
+	"testing"
+
+	lol "github.com/kdy1997/go-lol"
+	. "github.com/smartystreets/goconvey/convey"
+	"golang.org/x/net/context"
+)
+
+func TestValidate(t *testing.T) {
+	Convey("Validate", t, func() {
+		client := lol.New(nil, "test-key")
+		ctx := context.TODO()
+
+		Convey("Accepts valid calls", func() {
+			So(client.Challenger(ctx, lol.NA).Type("RANKED_SOLO_5x5").Validate(), ShouldBeNil)
+			So(client.Champions(ctx, lol.NA).Locale("ko_KR").ChampData("image,spells").Validate(), ShouldBeNil)
+			So(client.MatchesBySummonerID(ctx, lol.NA, 1).BeginTime(1).EndTime(2).Validate(), ShouldBeNil)
+		})
+
+		Convey("Rejects missing required parameters", func() {
+			err := client.Challenger(ctx, lol.NA).Validate()
+			So(err, ShouldResemble, &lol.ValidationError{
+				Operation: "Challenger",
+				Params:    []lol.ParamError{{Param: "type", Reason: "required"}},
+			})
+		})
+
+		Convey("Rejects empty lists and zero ids", func() {
+			So(client.SummonersByName(ctx, lol.NA, nil).Validate(), ShouldNotBeNil)
+			So(client.RecentGames(ctx, lol.NA, 0).Validate(), ShouldNotBeNil)
+		})
+
+		Convey("Rejects too long lists", func() {
+			ids := make([]int64, 41)
+			for i := range ids {
+				ids[i] = int64(i + 1)
+			}
+			So(client.Summoners(ctx, lol.NA, ids).Validate(), ShouldNotBeNil)
+			So(client.Summoners(ctx, lol.NA, ids[:40]).Validate(), ShouldBeNil)
+		})
+
+		Convey("Lists every problem", func() {
+			err := client.Champions(ctx, lol.NA).Locale("xx_XX").ChampData("image,unknown").Validate()
+			So(err, ShouldHaveSameTypeAs, &lol.ValidationError{})
+			So(err.(*lol.ValidationError).Params, ShouldHaveLength, 2)
+		})
+
+		Convey("Requires beginTime and endTime together", func() {
+			err := client.MatchesBySummonerID(ctx, lol.NA, 1).BeginTime(1).Validate()
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Do returns the error without sending a request", func() {
+			_, err := client.Challenger(ctx, lol.NA).Type("NORMAL_5x5").Do()
+			So(err, ShouldHaveSameTypeAs, &lol.ValidationError{})
+		})
+	})
+}